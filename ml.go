@@ -6,9 +6,7 @@ package goml
 
 import (
 	. "golog"
-	"math/rand"
 	"reflect"
-	"time"
 )
 
 //
@@ -66,6 +64,8 @@ func Size(data Data) (rows int, cols int) {
 	case float64:
 		rows = 1
 		cols = 1
+	case *Dense:
+		rows, cols = data.(*Dense).Dims()
 	default:
 		LogWarnf("unhandled: %s", reflect.TypeOf(data))
 	}
@@ -74,7 +74,10 @@ func Size(data Data) (rows int, cols int) {
 }
 
 func Equal(x Data, y Data) (result bool) {
-	bitmask := _argBitmask(x, y)
+	x, _ = _undense(x)
+	y, _ = _undense(y)
+
+	bitmask, _ := _argBitmask("==", x, y)
 	switch bitmask {
 	case ARG1_MATRIX | ARG2_MATRIX:
 		result = _equalMM(x.(Matrix), y.(Matrix))
@@ -125,6 +128,10 @@ func _equalMM(x Matrix, y Matrix) (result bool) {
 // Transpose a vector or matrix.
 //
 func Transpose(inputData Data) (data Data) {
+	if dense, ok := inputData.(*Dense); ok {
+		return dense.T()
+	}
+
 	// invert the col and row vals
 	inputVector := false
 	outputVector := false
@@ -260,17 +267,48 @@ func Cols(inmatrix Matrix, colFrom int, colTo int) (matrix Matrix) {
 }
 
 //
-// Creates a matrix filled with 1's.
+// Creates a matrix filled with uniform random values in [0.0, 1.0).
 //
 func Rand(rows int, cols int) (matrix Matrix) {
-	matrix = NewMatrix(rows, cols)
+	return RandWith(DefaultRNG, rows, cols)
+}
 
-	rand.Seed(time.Now().UTC().UnixNano())
+//
+// Creates a matrix filled with uniform random values in [0.0, 1.0)
+// drawn from rng.
+//
+func RandWith(rng *RNG, rows int, cols int) (matrix Matrix) {
+	rng = _rng(rng)
+	matrix = NewMatrix(rows, cols)
 
 	// looks awkward, but it's the most performant way
 	for _, row := range matrix {
 		for i := range row {
-			row[i] = rand.Float64()
+			row[i] = rng.Float64()
+		}
+	}
+
+	return matrix
+}
+
+//
+// Creates a matrix filled with standard normal random values.
+//
+func Randn(rows int, cols int) (matrix Matrix) {
+	return RandnWith(DefaultRNG, rows, cols)
+}
+
+//
+// Creates a matrix filled with standard normal random values
+// drawn from rng.
+//
+func RandnWith(rng *RNG, rows int, cols int) (matrix Matrix) {
+	rng = _rng(rng)
+	matrix = NewMatrix(rows, cols)
+
+	for _, row := range matrix {
+		for i := range row {
+			row[i] = rng.Normal(0, 1)
 		}
 	}
 
@@ -5,8 +5,12 @@
 package goml
 
 import (
+	"fmt"
 	. "golog"
 	"math"
+	"reflect"
+	"runtime"
+	"sync"
 )
 
 //
@@ -21,50 +25,69 @@ func DotAdd(arg1 Data, arg2 Data) (sum Data) {
 // Generic addition method
 //
 func Add(arg1 Data, arg2 Data) (sum Data) {
-	flags := _argBitmask(arg1, arg2)
+	sum, err := AddE(arg1, arg2)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+	}
+
+	return sum
+}
+
+//
+// Generic addition method, returning a DimensionError or TypeError
+// for nonconformant or unhandled arguments rather than logging
+//
+func AddE(arg1 Data, arg2 Data) (sum Data, err error) {
+	if _hasDense(arg1, arg2) {
+		return _denseOp(AddE, arg1, arg2)
+	}
+
+	flags, err := _argBitmask("+", arg1, arg2)
+	if err != nil {
+		return nil, err
+	}
 
 	switch flags {
 	case ARG1_MATRIX | ARG2_MATRIX:
 		LogDebug("AddMM")
-		sum = _addMM(arg1.(Matrix), arg2.(Matrix))
+		sum, err = _dataM(_addMM(arg1.(Matrix), arg2.(Matrix)))
 	case ARG1_VECTOR | ARG2_VECTOR:
 		LogDebug("AddVV")
-		sum = _addVV(arg1.(Vector), arg2.(Vector))
+		sum, err = _dataV(_addVV(arg1.(Vector), arg2.(Vector)))
 	case ARG1_MATRIX | ARG2_VECTOR:
 		LogDebug("AddMV")
-		sum = _addMV(arg1.(Matrix), arg2.(Vector))
+		sum, err = _dataM(_addMV(arg1.(Matrix), arg2.(Vector)))
 	case ARG1_VECTOR | ARG2_MATRIX:
 		LogDebug("AddVM")
-		sum = _addMV(arg2.(Matrix), arg1.(Vector))
+		sum, err = _dataM(_addMV(arg2.(Matrix), arg1.(Vector)))
 	case ARG1_MATRIX | ARG2_SCALAR:
 		LogDebug("AddMS")
-		sum = _addMS(arg1.(Matrix), arg2.(float64))
+		sum = _addMS(arg1.(Matrix), _scalar(arg2))
 	case ARG1_SCALAR | ARG2_MATRIX:
 		LogDebug("AddSM")
-		sum = _addMS(arg2.(Matrix), arg1.(float64))
+		sum = _addMS(arg2.(Matrix), _scalar(arg1))
 	case ARG1_VECTOR | ARG2_SCALAR:
 		LogDebug("AddVS")
-		sum = _addVS(arg1.(Vector), arg2.(float64))
+		sum = _addVS(arg1.(Vector), _scalar(arg2))
 	case ARG1_SCALAR | ARG2_VECTOR:
 		LogDebug("AddSV")
-		sum = _addVS(arg2.(Vector), arg1.(float64))
+		sum = _addVS(arg2.(Vector), _scalar(arg1))
 	default:
-		LogError("Unhandled argument type / combination")
+		err = &TypeError{Op: "+", Type1: reflect.TypeOf(arg1), Type2: reflect.TypeOf(arg2)}
 	}
 
-	return sum
+	return sum, err
 }
 
 //
 // Creates a matrix of the sums of two matricies.
 //
-func _addMM(matrix Matrix, matrix2 Matrix) (sum Matrix) {
+func _addMM(matrix Matrix, matrix2 Matrix) (sum Matrix, err error) {
 	rows, cols := Size(matrix)
 	rows2, cols2 := Size(matrix2)
 
 	if rows != rows2 || cols != cols2 {
-		LogErrorf("error: operator +: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
-		return sum
+		return sum, _dimensionError("+", matrix, matrix2)
 	}
 
 	sum = NewMatrix(rows, cols)
@@ -75,19 +98,18 @@ func _addMM(matrix Matrix, matrix2 Matrix) (sum Matrix) {
 		}
 	}
 
-	return sum
+	return sum, nil
 }
 
 //
 // Creates a vector of the sums of two vectors.
 //
-func _addVV(vector Vector, vector2 Vector) (sum Vector) {
-	rows, cols := Size(vector)
-	rows2, cols2 := Size(vector2)
+func _addVV(vector Vector, vector2 Vector) (sum Vector, err error) {
+	_, cols := Size(vector)
+	_, cols2 := Size(vector2)
 
 	if cols != cols2 {
-		LogErrorf("error: operator +: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
-		return sum
+		return sum, _dimensionError("+", vector, vector2)
 	}
 
 	sum = NewVector(cols)
@@ -96,20 +118,19 @@ func _addVV(vector Vector, vector2 Vector) (sum Vector) {
 		sum[i] = val + vector2[i]
 	}
 
-	return sum
+	return sum, nil
 }
 
 //
 // Creates a vector of the sum of a matrix and a vector
 // NOTE: arg order is irrelevant
 //
-func _addMV(matrix Matrix, vector Vector) (sum Matrix) {
+func _addMV(matrix Matrix, vector Vector) (sum Matrix, err error) {
 	rows, cols := Size(matrix)
-	rows2, cols2 := Size(vector)
+	_, cols2 := Size(vector)
 
 	if cols != cols2 {
-		LogErrorf("error: operator +: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
-		return sum
+		return sum, _dimensionError("+", matrix, vector)
 	}
 
 	sum = NewMatrix(rows, cols2)
@@ -120,7 +141,7 @@ func _addMV(matrix Matrix, vector Vector) (sum Matrix) {
 		}
 	}
 
-	return sum
+	return sum, nil
 }
 
 //
@@ -166,45 +187,71 @@ func DotSub(arg1 Data, arg2 Data) (diff Data) {
 // Generic subtraction method
 //
 func Sub(arg1 Data, arg2 Data) (diff Data) {
-	flags := _argBitmask(arg1, arg2)
+	diff, err := SubE(arg1, arg2)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+	}
+
+	return diff
+}
+
+//
+// Generic subtraction method, returning a DimensionError or TypeError
+// for nonconformant or unhandled arguments rather than logging
+//
+func SubE(arg1 Data, arg2 Data) (diff Data, err error) {
+	if _hasDense(arg1, arg2) {
+		return _denseOp(SubE, arg1, arg2)
+	}
+
+	flags, err := _argBitmask("-", arg1, arg2)
+	if err != nil {
+		return nil, err
+	}
 
 	switch flags {
 	case ARG1_MATRIX | ARG2_MATRIX:
 		LogDebug("SubMM")
-		diff = _subMM(arg1.(Matrix), arg2.(Matrix))
+		diff, err = _dataM(_subMM(arg1.(Matrix), arg2.(Matrix)))
 	case ARG1_VECTOR | ARG2_VECTOR:
 		LogDebug("SubVV")
-		diff = _subVV(arg1.(Vector), arg2.(Vector))
+		diff, err = _dataV(_subVV(arg1.(Vector), arg2.(Vector)))
 	case ARG1_MATRIX | ARG2_VECTOR:
 		LogDebug("SubMV")
-		diff = _subMV(arg1.(Matrix), arg2.(Vector))
+		diff, err = _dataM(_subMV(arg1.(Matrix), arg2.(Vector)))
 	case ARG1_VECTOR | ARG2_MATRIX:
 		LogDebug("SubVM")
-		diff = _subVM(arg1.(Vector), arg2.(Matrix))
+		diff, err = _dataM(_subVM(arg1.(Vector), arg2.(Matrix)))
 	case ARG1_MATRIX | ARG2_SCALAR:
 		LogDebug("SubMS")
-		diff = _subMS(arg1.(Matrix), arg2.(float64))
+		diff = _subMS(arg1.(Matrix), _scalar(arg2))
 	case ARG1_SCALAR | ARG2_MATRIX:
 		LogDebug("SubSM")
-		diff = _subSM(arg1.(float64), arg2.(Matrix))
+		diff = _subSM(_scalar(arg1), arg2.(Matrix))
 	case ARG1_VECTOR | ARG2_SCALAR:
 		LogDebug("SubVS")
-		diff = _subVS(arg1.(Vector), arg2.(float64))
+		diff = _subVS(arg1.(Vector), _scalar(arg2))
 	case ARG1_SCALAR | ARG2_VECTOR:
 		LogDebug("SubSV")
-		diff = _subSV(arg1.(float64), arg2.(Vector))
+		diff = _subSV(_scalar(arg1), arg2.(Vector))
 	default:
-		LogError("Unhandled argument type / combination")
+		err = &TypeError{Op: "-", Type1: reflect.TypeOf(arg1), Type2: reflect.TypeOf(arg2)}
 	}
 
-	return diff
+	return diff, err
 }
 
 //
 // Creates a matrix of the differences of two matricies.
 //
-func _subMM(matrix Matrix, matrix2 Matrix) (diff Matrix) {
+func _subMM(matrix Matrix, matrix2 Matrix) (diff Matrix, err error) {
 	rows, cols := Size(matrix)
+	rows2, cols2 := Size(matrix2)
+
+	if rows != rows2 || cols != cols2 {
+		return diff, _dimensionError("-", matrix, matrix2)
+	}
+
 	diff = NewMatrix(rows, cols)
 
 	for i, row := range matrix {
@@ -213,33 +260,38 @@ func _subMM(matrix Matrix, matrix2 Matrix) (diff Matrix) {
 		}
 	}
 
-	return diff
+	return diff, nil
 }
 
 //
 // Creates a vector of the differences of two vectors.
 //
-func _subVV(vector Vector, vector2 Vector) (diff Vector) {
+func _subVV(vector Vector, vector2 Vector) (diff Vector, err error) {
 	_, cols := Size(vector)
+	_, cols2 := Size(vector2)
+
+	if cols != cols2 {
+		return diff, _dimensionError("-", vector, vector2)
+	}
+
 	diff = NewVector(cols)
 
 	for i, val := range vector {
 		diff[i] = val - vector2[i]
 	}
 
-	return diff
+	return diff, nil
 }
 
 //
 // Creates a vector of the differences of two vectors.
 //
-func _subMV(matrix Matrix, vector Vector) (diff Matrix) {
+func _subMV(matrix Matrix, vector Vector) (diff Matrix, err error) {
 	rows, cols := Size(matrix)
-	rows2, cols2 := Size(vector)
+	_, cols2 := Size(vector)
 
 	if cols != cols2 {
-		LogErrorf("error: operator -: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
-		return diff
+		return diff, _dimensionError("-", matrix, vector)
 	}
 
 	diff = NewMatrix(rows, cols)
@@ -250,19 +302,18 @@ func _subMV(matrix Matrix, vector Vector) (diff Matrix) {
 		}
 	}
 
-	return diff
+	return diff, nil
 }
 
 //
 // Creates a vector of the differences of two vectors.
 //
-func _subVM(vector Vector, matrix Matrix) (diff Matrix) {
-	rows, cols := Size(vector)
+func _subVM(vector Vector, matrix Matrix) (diff Matrix, err error) {
+	_, cols := Size(vector)
 	rows2, cols2 := Size(matrix)
 
 	if cols != cols2 {
-		LogErrorf("error: operator -: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
-		return diff
+		return diff, _dimensionError("-", vector, matrix)
 	}
 
 	diff = NewMatrix(rows2, cols2)
@@ -273,7 +324,7 @@ func _subVM(vector Vector, matrix Matrix) (diff Matrix) {
 		}
 	}
 
-	return diff
+	return diff, nil
 }
 
 //
@@ -344,52 +395,69 @@ func _subSV(scalar float64, vector Vector) (diff Vector) {
 // Generic element-wise multiplication method
 //
 func DotMul(arg1 Data, arg2 Data) (prod Data) {
-	flags := _argBitmask(arg1, arg2)
+	prod, err := DotMulE(arg1, arg2)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+	}
+
+	return prod
+}
+
+//
+// Generic element-wise multiplication method, returning a DimensionError
+// or TypeError for nonconformant or unhandled arguments rather than logging
+//
+func DotMulE(arg1 Data, arg2 Data) (prod Data, err error) {
+	if _hasDense(arg1, arg2) {
+		return _denseOp(DotMulE, arg1, arg2)
+	}
+
+	flags, err := _argBitmask(".*", arg1, arg2)
+	if err != nil {
+		return nil, err
+	}
 
 	switch flags {
 	case ARG1_MATRIX | ARG2_MATRIX:
 		LogDebug("MulMM")
-		prod = _dotMulMM(arg1.(Matrix), arg2.(Matrix))
+		prod, err = _dataM(_dotMulMM(arg1.(Matrix), arg2.(Matrix)))
 	case ARG1_VECTOR | ARG2_VECTOR:
 		LogDebug("MulVV")
-		prod = _mulVV(arg1.(Vector), arg2.(Vector))
+		prod, err = _dataV(_mulVV(arg1.(Vector), arg2.(Vector)))
 	case ARG1_MATRIX | ARG2_VECTOR:
 		LogDebug("MulMV")
-		rows, cols := Size(arg1)
-		rows2, cols2 := Size(arg2)
-		LogErrorf("error: operator *: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
+		err = _dimensionError(".*", arg1, arg2)
 	case ARG1_VECTOR | ARG2_MATRIX:
 		LogDebug("MulVM")
-		prod = _mulVM(arg1.(Vector), arg2.(Matrix))
+		prod, err = _dataV(_mulVM(arg1.(Vector), arg2.(Matrix)))
 	case ARG1_MATRIX | ARG2_SCALAR:
 		LogDebug("MulMS")
-		prod = _mulSM(arg2.(float64), arg1.(Matrix))
+		prod = _mulSM(_scalar(arg2), arg1.(Matrix))
 	case ARG1_SCALAR | ARG2_MATRIX:
 		LogDebug("MulSM")
-		prod = _mulSM(arg1.(float64), arg2.(Matrix))
+		prod = _mulSM(_scalar(arg1), arg2.(Matrix))
 	case ARG1_VECTOR | ARG2_SCALAR:
 		LogDebug("MulVS")
-		prod = _mulSV(arg2.(float64), arg1.(Vector))
+		prod = _mulSV(_scalar(arg2), arg1.(Vector))
 	case ARG1_SCALAR | ARG2_VECTOR:
 		LogDebug("SV")
-		prod = _mulSV(arg1.(float64), arg2.(Vector))
+		prod = _mulSV(_scalar(arg1), arg2.(Vector))
 	default:
-		LogError("Unhandled argument type / combination")
+		err = &TypeError{Op: ".*", Type1: reflect.TypeOf(arg1), Type2: reflect.TypeOf(arg2)}
 	}
 
-	return prod
+	return prod, err
 }
 
 //
 // Creates a matrix of the products of two matricies.
 //
-func _dotMulMM(matrix Matrix, matrix2 Matrix) (prod Matrix) {
+func _dotMulMM(matrix Matrix, matrix2 Matrix) (prod Matrix, err error) {
 	rows, cols := Size(matrix)
 	rows2, cols2 := Size(matrix2)
 
-	if rows != rows || cols != cols2 {
-		LogErrorf("error: operator *: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
-		return prod
+	if rows != rows2 || cols != cols2 {
+		return prod, _dimensionError(".*", matrix, matrix2)
 	}
 
 	prod = NewMatrix(rows, cols)
@@ -401,83 +469,165 @@ func _dotMulMM(matrix Matrix, matrix2 Matrix) (prod Matrix) {
 		}
 	}
 
-	return prod
+	return prod, nil
 }
 
 //
 // Generic multiplication method
 //
 func Mul(arg1 Data, arg2 Data) (prod Data) {
-	flags := _argBitmask(arg1, arg2)
+	prod, err := MulE(arg1, arg2)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+	}
+
+	return prod
+}
+
+//
+// Generic multiplication method, returning a DimensionError or TypeError
+// for nonconformant or unhandled arguments rather than logging
+//
+func MulE(arg1 Data, arg2 Data) (prod Data, err error) {
+	if _hasDense(arg1, arg2) {
+		return _denseOp(MulE, arg1, arg2)
+	}
+
+	flags, err := _argBitmask("*", arg1, arg2)
+	if err != nil {
+		return nil, err
+	}
 
 	switch flags {
 	case ARG1_MATRIX | ARG2_MATRIX:
 		LogDebug("MulMM")
-		prod = _mulMM(arg1.(Matrix), arg2.(Matrix))
+		prod, err = _dataM(_mulMM(arg1.(Matrix), arg2.(Matrix)))
 	case ARG1_VECTOR | ARG2_VECTOR:
 		LogDebug("MulVV")
-		prod = _mulVV(arg1.(Vector), arg2.(Vector))
+		prod, err = _dataV(_mulVV(arg1.(Vector), arg2.(Vector)))
 	case ARG1_MATRIX | ARG2_VECTOR:
 		LogDebug("MulMV")
-		rows, cols := Size(arg1)
-		rows2, cols2 := Size(arg2)
-		LogErrorf("error: operator *: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
+		err = _dimensionError("*", arg1, arg2)
 	case ARG1_VECTOR | ARG2_MATRIX:
 		LogDebug("MulVM")
-		prod = _mulVM(arg1.(Vector), arg2.(Matrix))
+		prod, err = _dataV(_mulVM(arg1.(Vector), arg2.(Matrix)))
 	case ARG1_MATRIX | ARG2_SCALAR:
 		LogDebug("MulMS")
-		prod = _mulSM(arg2.(float64), arg1.(Matrix))
+		prod = _mulSM(_scalar(arg2), arg1.(Matrix))
 	case ARG1_SCALAR | ARG2_MATRIX:
 		LogDebug("MulSM")
-		prod = _mulSM(arg1.(float64), arg2.(Matrix))
+		prod = _mulSM(_scalar(arg1), arg2.(Matrix))
 	case ARG1_VECTOR | ARG2_SCALAR:
 		LogDebug("MulVS")
-		prod = _mulSV(arg2.(float64), arg1.(Vector))
+		prod = _mulSV(_scalar(arg2), arg1.(Vector))
 	case ARG1_SCALAR | ARG2_VECTOR:
 		LogDebug("SV")
-		prod = _mulSV(arg1.(float64), arg2.(Vector))
+		prod = _mulSV(_scalar(arg1), arg2.(Vector))
 	default:
-		LogError("Unhandled argument type / combination")
+		err = &TypeError{Op: "*", Type1: reflect.TypeOf(arg1), Type2: reflect.TypeOf(arg2)}
 	}
 
-	return prod
+	return prod, err
 }
 
+//
+// Tile edge used by the blocked matrix product, sized so a tile of
+// each operand stays resident in a typical L1/L2 cache
+//
+const _mulBlockSize = 64
+
+//
+// Number of multiply-adds (rows * inner * cols) above which the matrix
+// product is partitioned by rows across goroutines. Set to 0 to always
+// multiply on the calling goroutine.
+//
+var MulParallelThreshold = 1 << 18
+
 //
 // Creates a matrix of the products of two matricies.
 //
-func _mulMM(matrix Matrix, matrix2 Matrix) (prod Matrix) {
+func _mulMM(matrix Matrix, matrix2 Matrix) (prod Matrix, err error) {
 	rows, cols := Size(matrix)
 	rows2, cols2 := Size(matrix2)
 
 	if cols != rows2 {
-		LogErrorf("error: operator *: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
-		return prod
+		return prod, _dimensionError("*", matrix, matrix2)
 	}
 
-	prod = NewMatrix(len(matrix), 1)
+	prod = NewMatrix(rows, cols2)
 
-	// matrix * matrix multiplication => col[0] * row[0] + col[1] * row[1]
-	for i, row := range matrix {
-		for j, val := range row {
-			prod[i][0] += val * matrix2[j][0]
+	workers := runtime.GOMAXPROCS(0)
+	if workers > rows {
+		workers = rows
+	}
+
+	if MulParallelThreshold <= 0 || workers < 2 || rows*cols*cols2 < MulParallelThreshold {
+		_mulBlockMM(matrix, matrix2, prod, 0, rows)
+		return prod, nil
+	}
+
+	// each goroutine owns a disjoint band of result rows, so no locking
+	var wg sync.WaitGroup
+	band := (rows + workers - 1) / workers
+	for from := 0; from < rows; from += band {
+		to := from + band
+		if to > rows {
+			to = rows
 		}
+		wg.Add(1)
+		go func(from int, to int) {
+			defer wg.Done()
+			_mulBlockMM(matrix, matrix2, prod, from, to)
+		}(from, to)
 	}
+	wg.Wait()
 
-	return prod
+	return prod, nil
+}
+
+//
+// Accumulates rows rowFrom <= i < rowTo of matrix * matrix2 into prod.
+// The inner and column loops are tiled so each row of matrix2 is reused
+// from cache across the rows of the band.
+//
+func _mulBlockMM(matrix Matrix, matrix2 Matrix, prod Matrix, rowFrom int, rowTo int) {
+	inner, cols := Size(matrix2)
+
+	for kk := 0; kk < inner; kk += _mulBlockSize {
+		kTo := kk + _mulBlockSize
+		if kTo > inner {
+			kTo = inner
+		}
+		for jj := 0; jj < cols; jj += _mulBlockSize {
+			jTo := jj + _mulBlockSize
+			if jTo > cols {
+				jTo = cols
+			}
+			for i := rowFrom; i < rowTo; i++ {
+				row := matrix[i]
+				prodRow := prod[i]
+				// matrix * matrix multiplication => row[k] * matrix2[k][j] summed over k
+				for k := kk; k < kTo; k++ {
+					val := row[k]
+					row2 := matrix2[k]
+					for j := jj; j < jTo; j++ {
+						prodRow[j] += val * row2[j]
+					}
+				}
+			}
+		}
+	}
 }
 
 //
 // Creates a vector of the product of two vectors.
 //
-func _mulVV(vector Vector, vector2 Vector) (prod Vector) {
-	rows, cols := Size(vector)
-	rows2, cols2 := Size(vector2)
+func _mulVV(vector Vector, vector2 Vector) (prod Vector, err error) {
+	_, cols := Size(vector)
+	_, cols2 := Size(vector2)
 
 	if cols != cols2 {
-		LogErrorf("error: operator *: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
-		return prod
+		return prod, _dimensionError("*", vector, vector2)
 	}
 
 	prod = NewVector(cols)
@@ -486,14 +636,19 @@ func _mulVV(vector Vector, vector2 Vector) (prod Vector) {
 		prod[i] = val * vector2[i]
 	}
 
-	return prod
+	return prod, nil
 }
 
 //
 // Creates a matrix of the products of a value and matrix.
 //
-func _mulVM(vector Vector, matrix Matrix) (prod Vector) {
-	_, cols := Size(matrix)
+func _mulVM(vector Vector, matrix Matrix) (prod Vector, err error) {
+	rows, cols := Size(matrix)
+
+	if len(vector) != rows {
+		return prod, _dimensionError("*", vector, matrix)
+	}
+
 	prod = NewVector(cols)
 
 	// vetor * matrix multiplication => v[0] * m[0][0] + v[0] * m[1][0] + ...
@@ -503,7 +658,7 @@ func _mulVM(vector Vector, matrix Matrix) (prod Vector) {
 		}
 	}
 
-	return prod
+	return prod, nil
 }
 
 //
@@ -547,53 +702,71 @@ func DotDiv(arg1 Data, arg2 Data) (quot Data) {
 // Generic division method
 //
 func Div(arg1 Data, arg2 Data) (quot Data) {
-	flags := _argBitmask(arg1, arg2)
+	quot, err := DivE(arg1, arg2)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+	}
+
+	return quot
+}
+
+//
+// Generic division method, returning a DimensionError or TypeError
+// for nonconformant or unhandled arguments rather than logging
+//
+func DivE(arg1 Data, arg2 Data) (quot Data, err error) {
+	if _hasDense(arg1, arg2) {
+		return _denseOp(DivE, arg1, arg2)
+	}
+
+	flags, err := _argBitmask("/", arg1, arg2)
+	if err != nil {
+		return nil, err
+	}
 
 	switch flags {
 	case ARG1_MATRIX | ARG2_MATRIX:
 		LogDebug("DivMM")
-		quot = _divMM(arg1.(Matrix), arg2.(Matrix))
+		quot, err = _dataM(_divMM(arg1.(Matrix), arg2.(Matrix)))
 	case ARG1_VECTOR | ARG2_VECTOR:
 		LogDebug("DivVV")
-		rows, cols := Size(arg1)
-		rows2, cols2 := Size(arg2)
-		LogErrorf("error: operator /: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
+		err = _dimensionError("/", arg1, arg2)
 	case ARG1_MATRIX | ARG2_VECTOR:
 		LogDebug("DivMV")
-		rows, cols := Size(arg1)
-		rows2, cols2 := Size(arg2)
-		LogErrorf("error: operator /: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
+		err = _dimensionError("/", arg1, arg2)
 	case ARG1_VECTOR | ARG2_MATRIX:
 		LogDebug("DivVM")
-		quot = _divVM(arg1.(Vector), arg2.(Matrix))
+		quot, err = _dataV(_divVM(arg1.(Vector), arg2.(Matrix)))
 	case ARG1_MATRIX | ARG2_SCALAR:
 		LogDebug("DivMS")
-		quot = _divMS(arg1.(Matrix), arg2.(float64))
+		quot = _divMS(arg1.(Matrix), _scalar(arg2))
 	case ARG1_SCALAR | ARG2_MATRIX:
 		LogDebug("DivSM")
-		rows, cols := Size(arg1)
-		rows2, cols2 := Size(arg2)
-		LogErrorf("error: operator /: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
+		err = _dimensionError("/", arg1, arg2)
 	case ARG1_VECTOR | ARG2_SCALAR:
 		LogDebug("DivVS")
-		quot = _divVS(arg1.(Vector), arg2.(float64))
+		quot = _divVS(arg1.(Vector), _scalar(arg2))
 	case ARG1_SCALAR | ARG2_VECTOR:
 		LogDebug("DivSV")
-		rows, cols := Size(arg1)
-		rows2, cols2 := Size(arg2)
-		LogErrorf("error: operator /: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)\n", rows, cols, rows2, cols2)
+		err = _dimensionError("/", arg1, arg2)
 	default:
-		LogError("Unhandled argument type / combination")
+		err = &TypeError{Op: "/", Type1: reflect.TypeOf(arg1), Type2: reflect.TypeOf(arg2)}
 	}
 
-	return quot
+	return quot, err
 }
 
 //
 // Creates a matrix of the quotients of a matrix and a value.
 //
-func _divMM(matrix Matrix, matrix2 Matrix) (quot Matrix) {
+func _divMM(matrix Matrix, matrix2 Matrix) (quot Matrix, err error) {
 	rows, cols := Size(matrix)
+	rows2, cols2 := Size(matrix2)
+
+	if rows != rows2 || cols != cols2 {
+		return quot, _dimensionError("/", matrix, matrix2)
+	}
+
 	quot = NewMatrix(rows, cols)
 
 	for i, row := range matrix {
@@ -602,31 +775,48 @@ func _divMM(matrix Matrix, matrix2 Matrix) (quot Matrix) {
 		}
 	}
 
-	return quot
+	return quot, nil
 }
 
 //
 // Creates a matrix of the quotients of a matrix and a value.
 //
-func _divMV(matrix Matrix, vector Vector) (quot Matrix) {
+func _divMV(matrix Matrix, vector Vector) (quot Matrix, err error) {
 	rows, cols := Size(matrix)
+	_, cols2 := Size(vector)
+
+	if cols != cols2 {
+		return quot, _dimensionError("/", matrix, vector)
+	}
+
 	quot = NewMatrix(rows, cols)
 
 	// TBD
 
-	return quot
+	return quot, nil
 }
 
 //
-// Creates a matrix of the quotients of a matrix and a value.
+// Creates the vector x solving x * matrix = vector for a square matrix.
+// Octave: x = v / M
 //
-func _divVM(vector Vector, matrix Matrix) (quot Matrix) {
+func _divVM(vector Vector, matrix Matrix) (quot Vector, err error) {
 	rows, cols := Size(matrix)
-	quot = NewMatrix(rows, cols)
+	_, cols2 := Size(vector)
 
-	// This is very complicated
+	if cols != cols2 || rows != cols {
+		return quot, _dimensionError("/", vector, matrix)
+	}
 
-	return quot
+	// x * M = v is equivalent to M' * x' = v'
+	lu, pivot, _, singular := _lu(_transposeM(matrix))
+	if singular {
+		return quot, fmt.Errorf("operator /: %w", ErrSingular)
+	}
+
+	quot = Vector(_transposeM(_luSolve(lu, pivot, _transposeM(Matrix{vector})))[0])
+
+	return quot, nil
 }
 
 //
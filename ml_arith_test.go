@@ -5,6 +5,7 @@
 package goml
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -64,6 +65,48 @@ func TestMulMM(t *testing.T) {
 	}
 }
 
+func TestMulMMNonSquare(t *testing.T) {
+	x := Mul(Matrix{{1, 2, 3}, {4, 5, 6}}, Matrix{{7, 8}, {9, 10}, {11, 12}})
+	exp := Matrix{{58, 64}, {139, 154}}
+	if !Equal(x, exp) {
+		t.Errorf("%v != %v", x, exp)
+	}
+}
+
+func TestMulMMMultiColumn(t *testing.T) {
+	x := Mul(Matrix{{1, 2}, {3, 4}, {5, 6}}, Matrix{{1, 0, 2, -1}, {0, 1, 1, 3}})
+	exp := Matrix{{1, 2, 4, 5}, {3, 4, 10, 9}, {5, 6, 16, 13}}
+	if !Equal(x, exp) {
+		t.Errorf("%v != %v", x, exp)
+	}
+}
+
+func TestMulMMParallel(t *testing.T) {
+	// large enough to span several tiles and be split across goroutines
+	a := Seq(150, 70)
+	b := Seq(70, 90)
+
+	exp := NewMatrix(150, 90)
+	for i := range exp {
+		for j := range exp[i] {
+			for k := 0; k < 70; k++ {
+				exp[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+
+	threshold := MulParallelThreshold
+	defer func() { MulParallelThreshold = threshold }()
+
+	for _, threshold := range []int{0, 1} {
+		MulParallelThreshold = threshold
+		x := Mul(a, b)
+		if !Equal(x, exp) {
+			t.Errorf("product mismatch with threshold %d", threshold)
+		}
+	}
+}
+
 func TestMulVM(t *testing.T) {
 	x := Mul(Vector{1, 2}, Matrix{{1, 2, 3}, {4, 5, 6}})
 	exp := Vector{9, 12, 15}
@@ -87,3 +130,51 @@ func TestDivMS(t *testing.T) {
 		t.Errorf("%v != %v", x, exp)
 	}
 }
+
+func TestAddENonconformant(t *testing.T) {
+	x, err := AddE(Matrix{{1, 2, 3}, {4, 5, 6}}, Matrix{{1, 2}, {3, 4}, {5, 6}})
+	if x != nil {
+		t.Errorf("%v != nil", x)
+	}
+
+	var dimErr *DimensionError
+	if !errors.As(err, &dimErr) {
+		t.Fatalf("%v is not a DimensionError", err)
+	}
+	exp := DimensionError{Op: "+", Rows1: 2, Cols1: 3, Rows2: 3, Cols2: 2}
+	if *dimErr != exp {
+		t.Errorf("%v != %v", *dimErr, exp)
+	}
+}
+
+func TestMulENonconformant(t *testing.T) {
+	_, err := MulE(Vector{1, 2, 3}, Matrix{{1, 2}, {3, 4}})
+	var dimErr *DimensionError
+	if !errors.As(err, &dimErr) || dimErr.Op != "*" {
+		t.Errorf("%v is not a * DimensionError", err)
+	}
+}
+
+func TestDotMulENonconformant(t *testing.T) {
+	_, err := DotMulE(Matrix{{1, 2}, {3, 4}}, Matrix{{1, 2}})
+	var dimErr *DimensionError
+	if !errors.As(err, &dimErr) || dimErr.Op != ".*" {
+		t.Errorf("%v is not a .* DimensionError", err)
+	}
+}
+
+func TestSubEUnhandled(t *testing.T) {
+	_, err := SubE("a", Vector{1, 2})
+	var typeErr *TypeError
+	if !errors.As(err, &typeErr) || typeErr.Op != "-" {
+		t.Errorf("%v is not a - TypeError", err)
+	}
+}
+
+func TestDivEScalarInt(t *testing.T) {
+	x, err := DivE(Vector{2, 4, 6}, 2)
+	exp := Vector{1, 2, 3}
+	if err != nil || !Equal(x, exp) {
+		t.Errorf("%v != %v (%v)", x, exp, err)
+	}
+}
@@ -0,0 +1,294 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//
+// Options for LoadCSV, the zero value reads comma separated
+// values without a header
+//
+type CSVOptions struct {
+	// field separator, ',' when 0
+	Delimiter rune
+	// lines starting with this rune are ignored, 0 disables comments
+	Comment rune
+	// the first record holds the column names
+	Header bool
+	// cells read as NaN, "", "NA" and "NaN" when nil
+	Missing []string
+	// 1-based columns to keep, in order, all when empty
+	Select []int
+	// 1-based columns to drop, ignored with Select
+	Skip []int
+}
+
+//
+// Reported for a row with a different number of fields than the first
+//
+var ErrFieldCount = errors.New("wrong number of fields")
+
+var _csvMissing = []string{"", "NA", "NaN"}
+
+//
+// Streams the rows of delimited text, holding a single record in
+// memory, for datasets too large to load at once
+//
+type RowReader struct {
+	reader  *csv.Reader
+	opts    CSVOptions
+	missing []string
+	columns []int
+	width   int
+	header  []string
+	pending []string
+	err     error
+}
+
+//
+// Constructor, see LoadCSV for the options
+//
+func NewRowReader(r io.Reader, opts *CSVOptions) *RowReader {
+	rows := &RowReader{reader: csv.NewReader(r), width: -1}
+	if opts != nil {
+		rows.opts = *opts
+	}
+
+	if rows.opts.Delimiter != 0 {
+		rows.reader.Comma = rows.opts.Delimiter
+	}
+	rows.reader.Comment = rows.opts.Comment
+	rows.reader.FieldsPerRecord = -1
+	rows.reader.TrimLeadingSpace = true
+
+	rows.missing = rows.opts.Missing
+	if rows.missing == nil {
+		rows.missing = _csvMissing
+	}
+
+	return rows
+}
+
+//
+// Reads the first record, which sets the number of
+// columns and may hold the header. A first row that can't be
+// read is skipped like any other, the next call reading the
+// record after it.
+//
+func (self *RowReader) _start() error {
+	if self.width >= 0 || self.err != nil {
+		return self.err
+	}
+
+	record, err := self.reader.Read()
+	if err != nil {
+		if err == io.EOF || self.opts.Header {
+			self.err = err
+		}
+		return err
+	}
+
+	self.width = len(record)
+	self.columns, self.err = _csvColumns(self.width, &self.opts)
+	if self.err != nil {
+		return self.err
+	}
+
+	if self.opts.Header {
+		self.header = make([]string, len(self.columns))
+		for i, col := range self.columns {
+			self.header[i] = strings.TrimSpace(record[col])
+		}
+	} else {
+		self.pending = record
+	}
+
+	return nil
+}
+
+//
+// Header names of the selected columns, nil without opts.Header
+//
+func (self *RowReader) Header() (header []string, err error) {
+	if err = self._start(); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return self.header, nil
+}
+
+//
+// Reads the next row, returning io.EOF after the last one. A cell
+// that isn't a number is reported as a *ParseError with its line and
+// column, the following rows can still be read.
+//
+func (self *RowReader) Next() (row Vector, err error) {
+	if err = self._start(); err != nil {
+		return nil, err
+	}
+
+	record := self.pending
+	self.pending = nil
+	if record == nil {
+		if record, err = self.reader.Read(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(record) != self.width {
+		// locate the first missing or extra field
+		line, _ := self.reader.FieldPos(0)
+		col := len(record) + 1
+		if len(record) > self.width {
+			col = self.width + 1
+		}
+		return nil, &ParseError{Row: line, Col: col, Err: ErrFieldCount}
+	}
+
+	row = NewVector(len(self.columns))
+	for i, col := range self.columns {
+		row[i], err = _parseCell(record[col], self.missing)
+		if err != nil {
+			line, _ := self.reader.FieldPos(col)
+			return nil, &ParseError{Row: line, Col: col + 1, Value: record[col], Err: err}
+		}
+	}
+
+	return row, nil
+}
+
+//
+// Groups the rows of a RowReader into mini-batches, eg. for
+// stochastic gradient descent over a dataset on disk
+//
+type BatchReader struct {
+	rows *RowReader
+	size int
+}
+
+//
+// Constructor for batches of size rows, see LoadCSV for the options
+//
+func NewBatchReader(r io.Reader, opts *CSVOptions, size int) *BatchReader {
+	if size < 1 {
+		size = 1
+	}
+
+	return &BatchReader{rows: NewRowReader(r, opts), size: size}
+}
+
+//
+// Header names of the selected columns, nil without opts.Header
+//
+func (self *BatchReader) Header() (header []string, err error) {
+	return self.rows.Header()
+}
+
+//
+// Reads the next batch, which is short at the end of the input,
+// returning io.EOF once all the rows are read. A row that can't be
+// read ends the batch early: the rows read before it are returned
+// along with the error, and the next call resumes after it.
+//
+func (self *BatchReader) Next() (batch Matrix, err error) {
+	batch = NewEmptyMatrix(0)
+	for len(batch) < self.size {
+		row, err := self.rows.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if len(batch) == 0 {
+				return nil, err
+			}
+			return batch, err
+		}
+		batch = append(batch, row)
+	}
+
+	if len(batch) == 0 {
+		return nil, io.EOF
+	}
+
+	return batch, nil
+}
+
+//
+// Loads delimited text into a matrix, returning the header names of
+// the loaded columns when opts.Header is set. A cell that isn't a
+// number is reported as a *ParseError with its line and column.
+//
+func LoadCSV(r io.Reader, opts *CSVOptions) (matrix Matrix, header []string, err error) {
+	rows := NewRowReader(r, opts)
+	if header, err = rows.Header(); err != nil {
+		return nil, nil, err
+	}
+
+	matrix = NewEmptyMatrix(0)
+	for {
+		row, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		matrix = append(matrix, row)
+	}
+
+	return matrix, header, nil
+}
+
+//
+// Record indices of the columns selected by opts
+//
+func _csvColumns(width int, opts *CSVOptions) (columns []int, err error) {
+	for _, col := range append(append([]int{}, opts.Select...), opts.Skip...) {
+		if col < 1 || col > width {
+			return nil, fmt.Errorf("csv: column %d out of range [1, %d]", col, width)
+		}
+	}
+
+	if len(opts.Select) > 0 {
+		for _, col := range opts.Select {
+			columns = append(columns, col-1)
+		}
+		return columns, nil
+	}
+
+	skip := make(map[int]bool)
+	for _, col := range opts.Skip {
+		skip[col-1] = true
+	}
+	for col := 0; col < width; col++ {
+		if !skip[col] {
+			columns = append(columns, col)
+		}
+	}
+
+	return columns, nil
+}
+
+//
+// Parses a cell, reading the missing value markers as NaN
+//
+func _parseCell(cell string, missing []string) (val float64, err error) {
+	cell = strings.TrimSpace(cell)
+	for _, marker := range missing {
+		if cell == marker {
+			return math.NaN(), nil
+		}
+	}
+
+	return strconv.ParseFloat(cell, 64)
+}
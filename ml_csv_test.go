@@ -0,0 +1,223 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLoadCSV(t *testing.T) {
+	input := "# population, profit\nx;\"y\"\n6.1101; 17.592\n5.5277;9.1302\n"
+	matrix, header, err := LoadCSV(strings.NewReader(input), &CSVOptions{Delimiter: ';', Comment: '#', Header: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Matrix{{6.1101, 17.592}, {5.5277, 9.1302}}
+	if !Equal(matrix, exp) {
+		t.Errorf("%v != %v", matrix, exp)
+	}
+	if len(header) != 2 || header[0] != "x" || header[1] != "y" {
+		t.Errorf("header %v vs expected [x y]", header)
+	}
+}
+
+func TestLoadCSVMissing(t *testing.T) {
+	matrix, _, err := LoadCSV(strings.NewReader("1,NA,3\n4,,6\n"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsNaN(matrix[0][1]) || !math.IsNaN(matrix[1][1]) || matrix[1][2] != 6 {
+		t.Errorf("%v vs expected NaN in column 2", matrix)
+	}
+}
+
+func TestLoadCSVColumns(t *testing.T) {
+	input := "a,b,c\n1,2,3\n4,5,6\n"
+
+	matrix, header, err := LoadCSV(strings.NewReader(input), &CSVOptions{Header: true, Select: []int{3, 1}})
+	if err != nil || !Equal(matrix, Matrix{{3, 1}, {6, 4}}) || header[0] != "c" {
+		t.Errorf("%v %v != %v (%v)", header, matrix, Matrix{{3, 1}, {6, 4}}, err)
+	}
+
+	matrix, _, err = LoadCSV(strings.NewReader(input), &CSVOptions{Header: true, Skip: []int{2}})
+	if err != nil || !Equal(matrix, Matrix{{1, 3}, {4, 6}}) {
+		t.Errorf("%v != %v (%v)", matrix, Matrix{{1, 3}, {4, 6}}, err)
+	}
+
+	_, _, err = LoadCSV(strings.NewReader(input), &CSVOptions{Select: []int{4}})
+	if err == nil {
+		t.Errorf("column 4 of 3 vs expected error")
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	_, _, err := LoadCSV(strings.NewReader("1,2\n3,x\n"), nil)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Row != 2 || parseErr.Col != 2 {
+		t.Errorf("%v vs expected a parse error on line 2, column 2", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("%v is not %v", err, strconv.ErrSyntax)
+	}
+
+	_, _, err = LoadCSV(strings.NewReader("1,2\n3\n"), nil)
+	if !errors.Is(err, ErrFieldCount) {
+		t.Errorf("%v is not %v", err, ErrFieldCount)
+	}
+}
+
+func TestRowReader(t *testing.T) {
+	rows := NewRowReader(strings.NewReader("x,y\n1,2\n3,oops\n5,6\n"), &CSVOptions{Header: true})
+
+	header, err := rows.Header()
+	if err != nil || len(header) != 2 || header[1] != "y" {
+		t.Errorf("header %v vs expected [x y] (%v)", header, err)
+	}
+
+	row, err := rows.Next()
+	if err != nil || !Equal(row, Vector{1, 2}) {
+		t.Errorf("%v != %v (%v)", row, Vector{1, 2}, err)
+	}
+
+	// a bad cell fails its row only
+	_, err = rows.Next()
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Row != 3 {
+		t.Errorf("%v vs expected a parse error on line 3", err)
+	}
+
+	row, err = rows.Next()
+	if err != nil || !Equal(row, Vector{5, 6}) {
+		t.Errorf("%v != %v (%v)", row, Vector{5, 6}, err)
+	}
+
+	if _, err = rows.Next(); err != io.EOF {
+		t.Errorf("%v vs expected %v", err, io.EOF)
+	}
+}
+
+//
+// Generates n rows of "i,2*i" without holding them in memory
+//
+type _testRows struct {
+	n    int
+	row  int
+	line []byte
+}
+
+func (self *_testRows) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(self.line) == 0 {
+			if self.row == self.n {
+				if n == 0 {
+					return 0, io.EOF
+				}
+				break
+			}
+			self.line = []byte(strconv.Itoa(self.row) + "," + strconv.Itoa(2*self.row) + "\n")
+			self.row++
+		}
+		copied := copy(p[n:], self.line)
+		self.line = self.line[copied:]
+		n += copied
+	}
+
+	return n, nil
+}
+
+func TestRowReaderBadFirstRow(t *testing.T) {
+	in := "1,a\"b\n3,4\n5,6\n"
+
+	// the bare quote fails the first row only
+	rows := NewRowReader(strings.NewReader(in), nil)
+	if _, err := rows.Next(); err == nil {
+		t.Errorf("bare quote vs expected error")
+	}
+	for _, exp := range []Vector{{3, 4}, {5, 6}} {
+		if row, err := rows.Next(); err != nil || !Equal(row, exp) {
+			t.Fatalf("%v != %v (%v)", row, exp, err)
+		}
+	}
+	if _, err := rows.Next(); err != io.EOF {
+		t.Errorf("%v vs expected %v", err, io.EOF)
+	}
+
+	// batches skipping the bad row get to the end
+	batches := NewBatchReader(strings.NewReader(in), nil, 4)
+	read := Matrix{}
+	for i := 0; i < 4; i++ {
+		batch, err := batches.Next()
+		if err == io.EOF {
+			break
+		}
+		read = append(read, batch...)
+	}
+	if exp := (Matrix{{3, 4}, {5, 6}}); !Equal(read, exp) {
+		t.Errorf("%v != %v", read, exp)
+	}
+}
+
+func TestBatchReader(t *testing.T) {
+	batches := NewBatchReader(&_testRows{n: 10000}, nil, 256)
+
+	count, rows := 0, 0
+	for {
+		batch, err := batches.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if count < 39 && len(batch) != 256 || count == 39 && len(batch) != 16 {
+			t.Errorf("batch %d has %d rows", count, len(batch))
+		}
+		if batch[0][0] != float64(rows) || batch[0][1] != float64(2*rows) {
+			t.Errorf("batch %d starts with %v vs expected row %d", count, batch[0], rows)
+		}
+
+		count++
+		rows += len(batch)
+	}
+
+	if count != 40 || rows != 10000 {
+		t.Errorf("%d batches of %d rows vs expected 40 of 10000", count, rows)
+	}
+}
+
+func TestBatchReaderMalformed(t *testing.T) {
+	in := "1,2\n3,4\n5,x\n7,8\n9,10\n11,12\n"
+	batches := NewBatchReader(strings.NewReader(in), nil, 4)
+
+	rows := Matrix{}
+	errs := 0
+	for {
+		batch, err := batches.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var perr *ParseError
+			if !errors.As(err, &perr) || perr.Row != 3 {
+				t.Fatalf("%v vs expected a parse error on line 3", err)
+			}
+			errs++
+		}
+		rows = append(rows, batch...)
+	}
+
+	// every good row comes back, the partial batch with the error
+	exp := Matrix{{1, 2}, {3, 4}, {7, 8}, {9, 10}, {11, 12}}
+	if errs != 1 || !Equal(rows, exp) {
+		t.Errorf("%v with %d errors vs expected %v with 1", rows, errs, exp)
+	}
+}
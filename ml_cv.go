@@ -0,0 +1,155 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"fmt"
+	. "golog"
+	"strings"
+)
+
+//
+// A learner that can be trained and evaluated by CrossValidate.
+// Fit must start from scratch, since the same model is refit on
+// every fold. LinearRegression and LogisticRegression satisfy it.
+//
+type Model interface {
+	Fit(X Matrix, y Matrix)
+	Predict(X Matrix) Matrix
+}
+
+//
+// Scores predictions against the actual values, eg. accuracy for a
+// classifier or the negated mean squared error for a regression
+//
+type Metric func(actual Matrix, predicted Matrix) float64
+
+//
+// Row indices of the training and test sets of one fold
+//
+type Fold struct {
+	Train []int
+	Test  []int
+}
+
+//
+// Partitions the indices [0, n) into k folds of near equal size, each
+// fold holding out one part as its test set and training on the rest.
+// The first n % k test sets have one extra index.
+//
+func KFold(n int, k int, shuffle bool, rng *RNG) (folds []Fold) {
+	if k < 2 || k > n {
+		LogErrorf("error: kfold: %d folds for %d samples\n", k, n)
+		return nil
+	}
+
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	if shuffle {
+		indices = _rng(rng).Perm(n)
+	}
+
+	parts := make([][]int, k)
+	offset := 0
+	for f := range parts {
+		size := n / k
+		if f < n%k {
+			size++
+		}
+		parts[f] = indices[offset : offset+size]
+		offset += size
+	}
+
+	folds = _foldsFromParts(parts)
+
+	return folds
+}
+
+//
+// Partitions the indices of labels into k folds so that every test set
+// has close to the overall proportion of each label. Indices of each
+// label are dealt to the folds in turn, in ascending label order.
+//
+func StratifiedKFold(labels Vector, k int) (folds []Fold) {
+	if k < 2 || k > len(labels) {
+		LogErrorf("error: kfold: %d folds for %d samples\n", k, len(labels))
+		return nil
+	}
+
+	parts := make([][]int, k)
+	f := 0
+	for _, group := range _labelGroups(labels) {
+		for _, i := range group {
+			parts[f] = append(parts[f], i)
+			f = (f + 1) % k
+		}
+	}
+
+	folds = _foldsFromParts(parts)
+
+	return folds
+}
+
+//
+// Creates one fold per part, testing on the part and
+// training on all the others
+//
+func _foldsFromParts(parts [][]int) (folds []Fold) {
+	folds = make([]Fold, len(parts))
+
+	for f, part := range parts {
+		folds[f].Test = part
+		for g, other := range parts {
+			if g != f {
+				folds[f].Train = append(folds[f].Train, other...)
+			}
+		}
+	}
+
+	return folds
+}
+
+//
+// Per-fold and summary scores from CrossValidate
+//
+type CVResult struct {
+	Scores Vector
+	Mean   float64
+	Std    float64
+}
+
+//
+// Formats the result as the per-fold scores followed by mean ± std
+//
+func (self *CVResult) String() string {
+	scores := make([]string, len(self.Scores))
+	for i, score := range self.Scores {
+		scores[i] = fmt.Sprintf("%.4f", score)
+	}
+
+	return fmt.Sprintf("[%s] %.4f ± %.4f", strings.Join(scores, " "), self.Mean, self.Std)
+}
+
+//
+// Fits the model on the training rows of each fold and scores its
+// predictions for the test rows with metric
+//
+func CrossValidate(model Model, X Matrix, y Matrix, folds []Fold, metric Metric) (result *CVResult) {
+	result = &CVResult{Scores: NewVector(len(folds))}
+
+	for f, fold := range folds {
+		model.Fit(_selectRows(X, fold.Train), _selectRows(y, fold.Train))
+		predicted := model.Predict(_selectRows(X, fold.Test))
+		result.Scores[f] = metric(_selectRows(y, fold.Test), predicted)
+	}
+
+	scores := []float64(result.Scores)
+	result.Mean = Mean(&scores)
+	result.Std = StandardDeviation(&scores)
+
+	return result
+}
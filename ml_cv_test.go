@@ -0,0 +1,86 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestKFold(t *testing.T) {
+	folds := KFold(10, 3, false, nil)
+	if len(folds) != 3 {
+		t.Fatalf("%d folds vs expected %d", len(folds), 3)
+	}
+
+	sizes := []int{4, 3, 3}
+	tested := []int{}
+	for f, fold := range folds {
+		if len(fold.Test) != sizes[f] || len(fold.Train) != 10-sizes[f] {
+			t.Errorf("fold %d sizes %d : %d vs expected %d : %d", f, len(fold.Train), len(fold.Test), 10-sizes[f], sizes[f])
+		}
+		tested = append(tested, fold.Test...)
+	}
+
+	sort.Ints(tested)
+	for i, v := range tested {
+		if v != i {
+			t.Errorf("test sets %v do not cover 0..9 once", tested)
+			break
+		}
+	}
+}
+
+func TestKFoldShuffle(t *testing.T) {
+	x := KFold(20, 4, true, NewSeededRNG(9))
+	y := KFold(20, 4, true, NewSeededRNG(9))
+	for f := range x {
+		for i, v := range x[f].Test {
+			if v != y[f].Test[i] {
+				t.Errorf("same seed gave different folds")
+				return
+			}
+		}
+	}
+}
+
+func TestStratifiedKFold(t *testing.T) {
+	labels := Vector{0, 0, 0, 0, 0, 0, 1, 1, 1, 0, 0, 1}
+	folds := StratifiedKFold(labels, 4)
+
+	for f, fold := range folds {
+		positives := 0
+		for _, i := range fold.Test {
+			positives += int(labels[i])
+		}
+		if len(fold.Test) != 3 || positives != 1 {
+			t.Errorf("fold %d has %d of %d positive vs expected 1 of 3", f, positives, len(fold.Test))
+		}
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	X := Join(Ones(12, 1), Seq(12, 1)).(Matrix)
+	y := NewMatrix(12, 1)
+	for i, row := range X {
+		y[i][0] = 2*row[1] + 1
+	}
+
+	negMSE := func(actual Matrix, predicted Matrix) float64 {
+		r := 0.0
+		for i, row := range actual {
+			r -= (row[0] - predicted[i][0]) * (row[0] - predicted[i][0])
+		}
+		return r / float64(len(actual))
+	}
+
+	result := CrossValidate(NewLinearRegression(0.01, 5000), X, y, KFold(12, 3, true, NewSeededRNG(1)), negMSE)
+	if len(result.Scores) != 3 {
+		t.Fatalf("%d scores vs expected %d", len(result.Scores), 3)
+	}
+	if result.Mean < -0.01 {
+		t.Errorf("mean score %s vs expected ~0", result)
+	}
+}
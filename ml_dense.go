@@ -0,0 +1,250 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	. "golog"
+)
+
+//
+// Dense matrix backed by a single contiguous slice in row-major
+// order. Row i starts at data[i*stride], which lets row, column and
+// sub-matrix views share the backing slice without copying.
+//
+type Dense struct {
+	data   []float64
+	rows   int
+	cols   int
+	stride int
+}
+
+//
+// Creates a rows x cols dense matrix filled with 0's.
+//
+func NewDense(rows int, cols int) (dense *Dense) {
+	dense = &Dense{data: make([]float64, rows*cols), rows: rows, cols: cols, stride: cols}
+
+	return dense
+}
+
+//
+// Creates a rows x cols dense matrix over the row-major data slice.
+// The slice is used in place, not copied.
+//
+func NewDenseData(rows int, cols int, data []float64) (dense *Dense) {
+	if len(data) < rows*cols {
+		LogErrorf("error: dense: %d values cannot fill %dx%d", len(data), rows, cols)
+		return nil
+	}
+
+	dense = &Dense{data: data, rows: rows, cols: cols, stride: cols}
+
+	return dense
+}
+
+//
+// Creates a dense matrix holding a copy of a matrix.
+//
+func DenseFromMatrix(matrix Matrix) (dense *Dense) {
+	rows, cols := Size(matrix)
+	dense = NewDense(rows, cols)
+
+	for i, row := range matrix {
+		copy(dense.data[i*cols:(i+1)*cols], row)
+	}
+
+	return dense
+}
+
+//
+// Creates a 1 x n dense matrix over a vector. The vector is
+// used in place, not copied.
+//
+func DenseFromVector(vector Vector) (dense *Dense) {
+	dense = NewDenseData(1, len(vector), vector)
+
+	return dense
+}
+
+//
+// Size of the dense matrix as rows, cols.
+//
+func (self *Dense) Dims() (rows int, cols int) {
+	return self.rows, self.cols
+}
+
+//
+// Element at row i, col j (0-based).
+//
+func (self *Dense) At(i int, j int) float64 {
+	return self.data[i*self.stride+j]
+}
+
+//
+// Sets the element at row i, col j (0-based).
+//
+func (self *Dense) Set(i int, j int, val float64) {
+	self.data[i*self.stride+j] = val
+}
+
+//
+// Zero-copy view of row i (0-based) as a vector.
+//
+func (self *Dense) Row(i int) (vector Vector) {
+	offset := i * self.stride
+	vector = Vector(self.data[offset : offset+self.cols : offset+self.cols])
+
+	return vector
+}
+
+//
+// Zero-copy view of column j (0-based) as a rows x 1 dense matrix.
+//
+func (self *Dense) Col(j int) (dense *Dense) {
+	dense = self.View(0, j, self.rows, 1)
+
+	return dense
+}
+
+//
+// Zero-copy view of the rows x cols sub-matrix whose top left
+// element is at row i, col j (0-based).
+//
+func (self *Dense) View(i int, j int, rows int, cols int) (dense *Dense) {
+	if i < 0 || j < 0 || rows < 0 || cols < 0 || i+rows > self.rows || j+cols > self.cols {
+		LogErrorf("error: dense: view %dx%d at (%d,%d) out of bound %dx%d", rows, cols, i, j, self.rows, self.cols)
+		return nil
+	}
+
+	if rows == 0 || cols == 0 {
+		// an empty view has no elements to share, lay it out like
+		// NewDense(rows, cols) so that Row and At stay in bounds
+		dense = &Dense{data: []float64{}, rows: rows, cols: cols, stride: cols}
+		return dense
+	}
+
+	dense = &Dense{rows: rows, cols: cols, stride: self.stride}
+	offset := i*self.stride + j
+	dense.data = self.data[offset : offset+(rows-1)*self.stride+cols]
+
+	return dense
+}
+
+//
+// Creates a contiguous copy of the dense matrix.
+//
+func (self *Dense) Clone() (dense *Dense) {
+	dense = NewDense(self.rows, self.cols)
+
+	for i := 0; i < self.rows; i++ {
+		copy(dense.data[i*self.cols:(i+1)*self.cols], self.Row(i))
+	}
+
+	return dense
+}
+
+//
+// Matrix whose rows are zero-copy views of the dense rows, so writes
+// through either one are visible in both.
+//
+func (self *Dense) Matrix() (matrix Matrix) {
+	matrix = NewEmptyMatrix(self.rows)
+
+	for i := range matrix {
+		matrix[i] = self.Row(i)
+	}
+
+	return matrix
+}
+
+//
+// Unrolls the dense matrix into a vector. This is zero-copy when the
+// rows are contiguous, ie. the dense matrix is not a column or
+// sub-matrix view.
+//
+func (self *Dense) Vector() (vector Vector) {
+	if self.stride == self.cols || self.rows <= 1 {
+		vector = Vector(self.data[:self.rows*self.cols])
+	} else {
+		vector = Vector(self.Clone().data)
+	}
+
+	return vector
+}
+
+//
+// Reshape into a rows x cols dense matrix over the same data. This is
+// zero-copy when the rows are contiguous.
+//
+func (self *Dense) Reshape(rows int, cols int) (dense *Dense) {
+	if rows*cols != self.rows*self.cols {
+		LogErrorf("error: reshape: can't reshape %dx%d array to %dx%d array", self.rows, self.cols, rows, cols)
+		return nil
+	}
+
+	dense = NewDenseData(rows, cols, self.Vector())
+
+	return dense
+}
+
+//
+// Transpose of the dense matrix as a new dense matrix.
+//
+func (self *Dense) T() (dense *Dense) {
+	dense = NewDense(self.cols, self.rows)
+
+	for i := 0; i < self.rows; i++ {
+		for j, val := range self.Row(i) {
+			dense.data[j*dense.stride+i] = val
+		}
+	}
+
+	return dense
+}
+
+//
+// Checks whether either operand is a *Dense
+//
+func _hasDense(arg1 Data, arg2 Data) bool {
+	_, isDense1 := arg1.(*Dense)
+	_, isDense2 := arg2.(*Dense)
+
+	return isDense1 || isDense2
+}
+
+//
+// Replaces *Dense operands with zero-copy Matrix views so the
+// existing Matrix / Vector dispatch can handle them
+//
+func _undense(arg Data) (data Data, isDense bool) {
+	if dense, ok := arg.(*Dense); ok {
+		return dense.Matrix(), true
+	}
+
+	return arg, false
+}
+
+//
+// Applies a Data operation to operands that may be *Dense, converting
+// a matrix or vector result back to a *Dense when any operand was one
+//
+func _denseOp(op func(Data, Data) (Data, error), arg1 Data, arg2 Data) (result Data, err error) {
+	arg1, isDense1 := _undense(arg1)
+	arg2, isDense2 := _undense(arg2)
+
+	result, err = op(arg1, arg2)
+	if err != nil || !(isDense1 || isDense2) {
+		return result, err
+	}
+
+	switch result.(type) {
+	case Matrix:
+		result = DenseFromMatrix(result.(Matrix))
+	case Vector:
+		result = DenseFromVector(result.(Vector))
+	}
+
+	return result, nil
+}
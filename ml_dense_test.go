@@ -0,0 +1,122 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"testing"
+)
+
+func TestDenseFromMatrix(t *testing.T) {
+	x := DenseFromMatrix(Matrix{{1, 2, 3}, {4, 5, 6}})
+	rows, cols := Size(x)
+	if rows != 2 || cols != 3 {
+		t.Errorf("%dx%d vs expected 2x3", rows, cols)
+	}
+
+	exp := Matrix{{1, 2, 3}, {4, 5, 6}}
+	if !Equal(x.Matrix(), exp) {
+		t.Errorf("%v != %v", x.Matrix(), exp)
+	}
+}
+
+func TestDenseViews(t *testing.T) {
+	x := DenseFromMatrix(Seq(3, 4))
+
+	row := x.Row(1)
+	if !Equal(row, Vector{5, 6, 7, 8}) {
+		t.Errorf("%v != %v", row, Vector{5, 6, 7, 8})
+	}
+
+	col := x.Col(2)
+	if !Equal(col.Vector(), Vector{3, 7, 11}) {
+		t.Errorf("%v != %v", col.Vector(), Vector{3, 7, 11})
+	}
+
+	sub := x.View(1, 1, 2, 2)
+	if !Equal(sub.Matrix(), Matrix{{6, 7}, {10, 11}}) {
+		t.Errorf("%v != %v", sub.Matrix(), Matrix{{6, 7}, {10, 11}})
+	}
+
+	// views share the backing slice
+	sub.Set(0, 0, 60)
+	col.Set(2, 0, 110)
+	row[3] = 80
+	exp := Matrix{{1, 2, 3, 4}, {5, 60, 7, 80}, {9, 10, 110, 12}}
+	if !Equal(x.Matrix(), exp) {
+		t.Errorf("%v != %v", x.Matrix(), exp)
+	}
+}
+
+func TestDenseReshape(t *testing.T) {
+	x := NewDenseData(2, 3, []float64{1, 2, 3, 4, 5, 6})
+	y := x.Reshape(3, 2)
+	exp := Matrix{{1, 2}, {3, 4}, {5, 6}}
+	if !Equal(y.Matrix(), exp) {
+		t.Errorf("%v != %v", y.Matrix(), exp)
+	}
+
+	y.Set(2, 1, 60)
+	if x.At(1, 2) != 60 {
+		t.Errorf("%f vs expected %f", x.At(1, 2), 60.0)
+	}
+}
+
+func TestDenseTranspose(t *testing.T) {
+	x := DenseFromMatrix(Matrix{{1, 2, 3, 4}, {5, 6, 7, 8}})
+	y := Transpose(x).(*Dense)
+	exp := Matrix{{1, 5}, {2, 6}, {3, 7}, {4, 8}}
+	if !Equal(y, exp) {
+		t.Errorf("%v != %v", y.Matrix(), exp)
+	}
+}
+
+func TestDenseArith(t *testing.T) {
+	x := DenseFromMatrix(Matrix{{1, 2}, {3, 4}, {5, 6}})
+
+	sum := Add(x, Matrix{{6, 5}, {4, 3}, {2, 1}}).(*Dense)
+	if !Equal(sum, Matrix{{7, 7}, {7, 7}, {7, 7}}) {
+		t.Errorf("%v != %v", sum.Matrix(), Matrix{{7, 7}, {7, 7}, {7, 7}})
+	}
+
+	prod := Mul(x, DenseFromMatrix(Matrix{{10}, {100}})).(*Dense)
+	if !Equal(prod, Matrix{{210}, {430}, {650}}) {
+		t.Errorf("%v != %v", prod.Matrix(), Matrix{{210}, {430}, {650}})
+	}
+
+	vprod := Mul(Vector{1, 2, 3}, x).(*Dense)
+	if !Equal(vprod.Vector(), Vector{22, 28}) {
+		t.Errorf("%v != %v", vprod.Vector(), Vector{22, 28})
+	}
+}
+
+func TestDenseEmptyViews(t *testing.T) {
+	x := DenseFromMatrix(Seq(3, 4))
+
+	// zero width
+	empty := x.View(0, 4, 3, 0)
+	if rows, cols := empty.Dims(); rows != 3 || cols != 0 {
+		t.Fatalf("%dx%d vs expected 3x0", rows, cols)
+	}
+	for i := 0; i < 3; i++ {
+		if len(empty.Row(i)) != 0 {
+			t.Errorf("row %d %v vs expected empty", i, empty.Row(i))
+		}
+	}
+	if len(empty.Matrix()) != 3 || len(empty.Vector()) != 0 || len(empty.Clone().Matrix()) != 3 {
+		t.Errorf("%v vs expected 3 empty rows", empty.Matrix())
+	}
+	if rows, cols := empty.T().Dims(); rows != 0 || cols != 3 {
+		t.Errorf("transpose %dx%d vs expected 0x3", rows, cols)
+	}
+
+	// zero height, below the last row
+	empty = x.View(3, 1, 0, 2)
+	if rows, cols := empty.Dims(); rows != 0 || cols != 2 {
+		t.Fatalf("%dx%d vs expected 0x2", rows, cols)
+	}
+	if len(empty.Matrix()) != 0 || len(empty.Vector()) != 0 {
+		t.Errorf("%v vs expected no rows", empty.Matrix())
+	}
+}
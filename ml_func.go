@@ -5,6 +5,7 @@
 package goml
 
 import (
+	. "golog"
 	"math"
 )
 
@@ -37,31 +38,40 @@ func ComputeLRCost(X Matrix, y Matrix, theta Matrix) (J float64) {
 // theta = theta - ((alpha * ((theta' * X') - y')) * X / m)'
 //
 func GradientDescent(X Matrix, y, intheta Matrix, alpha float64, num_iters int) (theta Matrix, J_history Vector) {
-	J_history = NewEmptyVector()
-
-	theta = intheta
-
-	m := len(y)
-	for i := 0; i < num_iters; i++ {
-		Jc := ComputeLRCost(X, y, theta)
+	return GradientDescentWith(X, y, intheta, NewGD(alpha), num_iters)
+}
 
-		tX := Mul(T(theta), T(X))
-		tXy := Sub(tX, T(y))
-		atXy := Mul(alpha, tXy)
-		atXyX := Mul(atXy, X)
-		atXyXm := Div(atXyX, float64(m))
-		theta = Sub(theta, T(atXyXm)).(Matrix)
+//
+// Closed form linear regression with optional L2 regularization, using
+// the same X, y and theta shapes as ComputeLRCost
+// theta = pinv(X' * X + lambda * I) * X' * y
+//
+func NormalEqn(X Matrix, y Matrix, lambda float64) (theta Matrix) {
+	Xt := _transposeM(X)
 
-		Jn := ComputeLRCost(X, y, theta)
+	XtX, err := _mulMM(Xt, X)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+		return nil
+	}
+	for i := range XtX {
+		XtX[i][i] += lambda
+	}
 
-		if Jn == Jc || Jn > Jc {
-			break
-		}
+	Xty, err := _mulMM(Xt, y)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+		return nil
+	}
 
-		J_history = append(J_history, Jn)
+	// the pseudo-inverse keeps redundant features from failing the solve
+	theta, err = _mulMM(Pinv(XtX), Xty)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+		return nil
 	}
 
-	return theta, J_history
+	return theta
 }
 
 //
@@ -75,18 +85,18 @@ func Sigmoid(z Data) (g Data) {
 		g = NewMatrix(row, col)
 		for i, row := range z.(Matrix) {
 			for j, val := range row {
-				g.([][]float64)[i][j] = 1 / (1 - math.Pow(e, -val))
+				g.(Matrix)[i][j] = 1 / (1 + math.Pow(e, -val))
 			}
 		}
 	case Vector, []float64:
 		_, col := Size(z)
 		g = NewVector(col)
 		for i, val := range z.(Vector) {
-			g.([]float64)[i] = 1 / (1 - math.Pow(e, -val))
+			g.(Vector)[i] = 1 / (1 + math.Pow(e, -val))
 		}
 	case float64:
 		// . for each
-		g = 1 / (1 - math.Pow(e, -z.(float64)))
+		g = 1 / (1 + math.Pow(e, -z.(float64)))
 	default:
 		// unhandled
 	}
@@ -94,8 +104,53 @@ func Sigmoid(z Data) (g Data) {
 	return g
 }
 
+//
+// Logistic Regression Cost Function and gradient, regularized by lambda
+// without penalizing the bias term theta[0]. The log is taken of h clamped
+// away from 0 and 1 so the cost stays finite for saturated predictions.
+// h = sigmoid(X * theta)
+// J = (1 / m) * sum(-y .* log(h) - (1 - y) .* log(1 - h)) + (lambda / (2 * m)) * sum(theta(2:end) .^ 2)
+// grad = (1 / m) * X' * (h - y) + (lambda / m) * [0; theta(2:end)]
+//
+func ComputeLogisticCost(X Matrix, y Matrix, theta Matrix, lambda float64) (J float64, grad Matrix) {
+	m := len(y)
+	n := len(theta)
+	grad = NewMatrix(n, 1)
+
+	for row := 0; row < m; row++ {
+		z := 0.0
+		for col := 0; col < n; col++ {
+			z += X[row][col] * theta[col][0]
+		}
+		h := Sigmoid(z).(float64)
+		hc := math.Min(math.Max(h, _eps), 1-_eps)
+
+		J += -y[row][0]*math.Log(hc) - (1-y[row][0])*math.Log(1-hc)
+		for col := 0; col < n; col++ {
+			grad[col][0] += (h - y[row][0]) * X[row][col]
+		}
+	}
+
+	reg := 0.0
+	for col := 1; col < n; col++ {
+		reg += theta[col][0] * theta[col][0]
+	}
+	J = J/float64(m) + (lambda/(2.0*float64(m)))*reg
+
+	for col := 0; col < n; col++ {
+		grad[col][0] /= float64(m)
+		if col > 0 {
+			grad[col][0] += (lambda / float64(m)) * theta[col][0]
+		}
+	}
+
+	return J, grad
+}
+
 //
 // Calculate the accuracy evaluation metric
+// ap is the 2x2 confusion matrix indexed [actual][predicted], with
+// index 0 for the positive class and 1 for the negative class
 // accuracy = P1A1 + P0A0 / (P1A1 + P1A0 + P0A1 + P0A0)
 //
 func Accuracy(ap [][]int) (accuracy float64) {
@@ -109,25 +164,26 @@ func Accuracy(ap [][]int) (accuracy float64) {
 // precision = P1A1 / (P1A1 + P1A0)
 //
 func Precision(ap [][]int) (precision float64) {
-	precision = float64(ap[0][0]) / float64(ap[0][0]+ap[0][1])
+	precision = float64(ap[0][0]) / float64(ap[0][0]+ap[1][0])
 
 	return precision
 }
 
 //
 // Calculate the recall evaluation metric
-// recall = P1A1 / (P1A1 + P1A1)
+// recall = P1A1 / (P1A1 + P0A1)
 //
 func Recall(ap [][]int) (recall float64) {
-	recall = float64(ap[0][0]) / float64(ap[0][0]+ap[1][0])
+	recall = float64(ap[0][0]) / float64(ap[0][0]+ap[0][1])
 
 	return recall
 }
 
 //
-// Calculate the F1 score evaluation metric
-// Determines the appropriate threshold to use for precision / recall
-// in an attempt to maximize the F1 score
+// Calculate the F1 score evaluation metric, the harmonic mean of
+// precision and recall. See BestThreshold to choose the threshold
+// that maximizes it.
+// f1 = 2 * precision * recall / (precision + recall)
 //
 func F1Score(ap [][]int) (f1 float64) {
 	precision := Precision(ap)
@@ -137,3 +193,15 @@ func F1Score(ap [][]int) (f1 float64) {
 
 	return f1
 }
+
+//
+// Calculate Youden's J statistic, the distance of the ROC point
+// above the chance diagonal
+// j = recall + specificity - 1 = P1A1 / (P1A1 + P0A1) - P1A0 / (P1A0 + P0A0)
+//
+func YoudenJ(ap [][]int) (j float64) {
+	fpr := float64(ap[1][0]) / float64(ap[1][0]+ap[1][1])
+	j = Recall(ap) - fpr
+
+	return j
+}
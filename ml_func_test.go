@@ -10,8 +10,91 @@ import (
 
 func TestSigmoid(t *testing.T) {
 	x := Sigmoid(1.0).(float64)
-	exp := 1.581977
+	exp := 0.731059
 	if Round(x, 6) != exp {
 		t.Errorf("Sigmoid of %f is not %f", x, exp)
 	}
 }
+
+func TestSigmoidMatrix(t *testing.T) {
+	x := Sigmoid(Matrix{{0, 1}, {-1, 0}})
+	exp := Matrix{{0.5, 0.731059}, {0.268941, 0.5}}
+	if !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v", x, exp)
+	}
+}
+
+func TestComputeLogisticCost(t *testing.T) {
+	X := Matrix{{1, 1}, {1, 2}, {1, 3}, {1, 4}}
+	y := Matrix{{0}, {0}, {1}, {1}}
+
+	J, grad := ComputeLogisticCost(X, y, Matrix{{0}, {0}}, 0)
+	_testA(t, J, 0.693147)
+	exp := Matrix{{0}, {-0.5}}
+	if !Equal(_roundData(grad, 6), exp) {
+		t.Errorf("%v != %v", grad, exp)
+	}
+}
+
+func TestComputeLogisticCostRegularized(t *testing.T) {
+	X := Matrix{{1, 1}, {1, 2}, {1, 3}, {1, 4}}
+	y := Matrix{{0}, {0}, {1}, {1}}
+	theta := Matrix{{1}, {1}}
+
+	J, grad := ComputeLogisticCost(X, y, theta, 0)
+	Jr, gradr := ComputeLogisticCost(X, y, theta, 1)
+
+	// only theta[1] is penalized: lambda / (2 * m) and lambda / m
+	_testA(t, Jr-J, 0.125)
+	_testA(t, gradr[0][0]-grad[0][0], 0)
+	_testA(t, gradr[1][0]-grad[1][0], 0.25)
+}
+
+func TestNormalEqn(t *testing.T) {
+	X := Matrix{{1, 1}, {1, 2}, {1, 3}}
+	y := Matrix{{2}, {3}, {4}}
+
+	theta := NormalEqn(X, y, 0)
+	exp := Matrix{{1}, {1}}
+	if !Equal(_roundData(theta, 6), exp) {
+		t.Errorf("%v != %v", theta, exp)
+	}
+
+	J := ComputeLRCost(X, y, theta)
+	if Round(J, 6) != 0 {
+		t.Errorf("cost %f vs expected %f", J, 0.0)
+	}
+}
+
+func TestNormalEqnRegularized(t *testing.T) {
+	X := Matrix{{1, 1}, {1, 2}, {1, 3}}
+	y := Matrix{{2}, {3}, {4}}
+
+	theta := NormalEqn(X, y, 1)
+	exp := Matrix{{0.625}, {1.083333}}
+	if !Equal(_roundData(theta, 6), exp) {
+		t.Errorf("%v != %v", theta, exp)
+	}
+}
+
+func TestNormalEqnRedundantFeature(t *testing.T) {
+	// the duplicated feature makes X' * X singular
+	X := Matrix{{1, 1, 1}, {1, 2, 2}, {1, 3, 3}}
+	y := Matrix{{2}, {3}, {4}}
+
+	theta := NormalEqn(X, y, 0)
+	exp := Matrix{{1}, {0.5}, {0.5}}
+	if !Equal(_roundData(theta, 6), exp) {
+		t.Errorf("%v != %v", theta, exp)
+	}
+}
+
+func TestBinaryMetrics(t *testing.T) {
+	// 8 true positives, 2 false negatives, 4 false positives, 6 true negatives
+	ap := [][]int{{8, 2}, {4, 6}}
+
+	_testA(t, Accuracy(ap), 0.7)
+	_testA(t, Precision(ap), 0.666667)
+	_testA(t, Recall(ap), 0.8)
+	_testA(t, F1Score(ap), 0.727273)
+}
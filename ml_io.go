@@ -5,46 +5,469 @@
 package goml
 
 import (
-	"io/ioutil"
-	"log"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	. "golog"
+	"io"
+	"math"
 	"os"
+	"reflect"
+	"regexp"
 	"strconv"
-	"strings"
 )
 
 //
-// Loads a matlab / comma delimited dataset into a matrix.
-// Assumptions: float64
+// Loads a comma delimited dataset into a matrix, logging the
+// error and returning nil if it can't be read. See LoadCSV
+// for other delimiters, headers and missing values,
+// LoadOctaveText for files saved by Octave / MATLAB and
+// NewBatchReader to stream files too large to load.
 //
 func Load(filePath string) (matrix Matrix) {
-	_, err := os.Stat(filePath)
-	if os.IsNotExist(err) {
-		log.Fatal(err)
+	file, err := os.Open(filePath)
+	if err != nil {
+		LogErrorf("error: %v\n", err)
+		return nil
+	}
+	defer file.Close()
+
+	matrix, _, err = LoadCSV(file, nil)
+	if err != nil {
+		LogErrorf("error: %s: %v\n", filePath, err)
+		return nil
+	}
+
+	return matrix
+}
+
+//
+// Formats written by Save
+//
+type Format int
+
+const (
+	// comma separated values, read by LoadCSV
+	FormatCSV Format = iota
+	// little-endian float64 values after a shape header, read by LoadBinary
+	FormatBinary
+	// nested arrays of numbers, read by LoadJSON
+	FormatJSON
+)
+
+//
+// Reported for binary input that wasn't written by Save
+//
+var ErrBinaryFormat = errors.New("not a goml binary file")
+
+var _binaryMagic = []byte("GOML")
+
+// most rows read for a matrix without columns, which no data bounds
+const _maxEmptyRows = 1 << 24
+
+//
+// Checks that every row is as long as the first, a ragged matrix
+// can't be saved with a single shape
+//
+func _checkRows(op string, rows [][]float64) (err error) {
+	for _, row := range rows {
+		if len(row) != len(rows[0]) {
+			return &DimensionError{Op: op, Rows1: 1, Cols1: len(rows[0]), Rows2: 1, Cols2: len(row)}
+		}
+	}
+
+	return nil
+}
+
+//
+// Rows of a vector or matrix to save
+//
+func _saveRows(op string, data Data) (rows [][]float64, isVector bool, err error) {
+	data, _ = _undense(data)
+
+	switch data.(type) {
+	case Vector:
+		return [][]float64{data.(Vector)}, true, nil
+	case Matrix:
+		if err = _checkRows(op, data.(Matrix)); err != nil {
+			return nil, false, err
+		}
+		return data.(Matrix), false, nil
+	}
+
+	return nil, false, &TypeError{Op: op, Type1: reflect.TypeOf(data)}
+}
+
+//
+// Writes a vector or matrix in format, streaming it row by row.
+// Every format reads the values back exactly, CSV being written by
+// SaveCSV with precision -1. Binary and JSON keep the type and shape,
+// except that JSON reads a matrix without rows as an empty vector.
+// CSV holds matrices only: a vector reads back as a 1xN matrix and
+// a matrix with rows but no columns can't be written.
+//
+func Save(w io.Writer, data Data, format Format) (err error) {
+	switch format {
+	case FormatCSV:
+		return SaveCSV(w, data, -1)
+	case FormatBinary:
+		return SaveBinary(w, data)
+	case FormatJSON:
+		return SaveJSON(w, data)
+	}
+
+	return fmt.Errorf("save: unknown format %d", format)
+}
+
+//
+// Writes a vector as a row or a matrix as comma separated values
+// with precision significant digits, -1 for the fewest digits
+// that read back exactly. LoadCSV reads either back as a matrix.
+//
+func SaveCSV(w io.Writer, data Data, precision int) (err error) {
+	rows, _, err := _saveRows("save csv", data)
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 && len(rows[0]) == 0 {
+		// blank lines would read back as no rows at all
+		return fmt.Errorf("save csv: %dx0 matrix has no values to write", len(rows))
+	}
+
+	writer := bufio.NewWriter(w)
+	for _, row := range rows {
+		for col, val := range row {
+			if col > 0 {
+				writer.WriteByte(',')
+			}
+			writer.WriteString(strconv.FormatFloat(val, 'g', precision, 64))
+		}
+		writer.WriteByte('\n')
+	}
+
+	return writer.Flush()
+}
+
+//
+// Writes a vector or matrix in the binary format: the "GOML" magic,
+// the number of dimensions and each dimension as little-endian
+// uint32s, then the row major float64 values
+//
+func SaveBinary(w io.Writer, data Data) (err error) {
+	rows, isVector, err := _saveRows("save binary", data)
+	if err != nil {
+		return err
+	}
+
+	shape := []uint32{2, uint32(len(rows)), 0}
+	if len(rows) > 0 {
+		shape[2] = uint32(len(rows[0]))
+	}
+	if isVector {
+		shape = []uint32{1, shape[2]}
+	}
+
+	writer := bufio.NewWriter(w)
+	writer.Write(_binaryMagic)
+	binary.Write(writer, binary.LittleEndian, shape)
+	for _, row := range rows {
+		writer.Write(_encodeNumbers(row, _float64, binary.LittleEndian))
+	}
+
+	return writer.Flush()
+}
+
+//
+// Reads a vector or matrix written by SaveBinary
+//
+func LoadBinary(r io.Reader) (data Data, err error) {
+	header := make([]byte, 8)
+	if _, err = io.ReadFull(r, header); err != nil || !bytes.Equal(header[:4], _binaryMagic) {
+		return nil, ErrBinaryFormat
+	}
+
+	ndims := binary.LittleEndian.Uint32(header[4:])
+	if ndims != 1 && ndims != 2 {
+		return nil, fmt.Errorf("load binary: %d dimensions: %w", ndims, ErrBinaryFormat)
+	}
+	shape := make([]uint32, ndims)
+	if err = binary.Read(r, binary.LittleEndian, shape); err != nil {
+		return nil, ErrBinaryFormat
+	}
+
+	if ndims == 1 {
+		shape = []uint32{1, shape[0]}
+	}
+
+	// the shape isn't trusted to size allocations, rows are appended
+	// as their values are read. Without columns there are no values
+	// to bound the rows, so their number is capped instead.
+	rows, cols := int64(shape[0]), int64(shape[1])
+	if cols > 0 && rows > math.MaxInt64/8/cols || cols == 0 && rows > _maxEmptyRows {
+		return nil, fmt.Errorf("load binary: %dx%d array: %w", rows, cols, ErrBinaryFormat)
+	}
+
+	matrix := NewEmptyMatrix(0)
+	for row := int64(0); row < rows; row++ {
+		raw, err := io.ReadAll(io.LimitReader(r, cols*8))
+		if err != nil || int64(len(raw)) != cols*8 {
+			return nil, fmt.Errorf("load binary: row %d: %w", row+1, io.ErrUnexpectedEOF)
+		}
+		matrix = append(matrix, _decodeNumbers(raw, _float64, binary.LittleEndian))
+	}
+
+	if ndims == 1 {
+		return Vector(matrix[0]), nil
+	}
+
+	return matrix, nil
+}
+
+//
+// JSON number that also reads and writes the "NaN", "+Inf" and
+// "-Inf" strings, which JSON numbers can't represent
+//
+type _jsonFloat float64
+
+func (self _jsonFloat) MarshalJSON() ([]byte, error) {
+	val := float64(self)
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return []byte(`"` + strconv.FormatFloat(val, 'g', -1, 64) + `"`), nil
+	}
+
+	return []byte(strconv.FormatFloat(val, 'g', -1, 64)), nil
+}
+
+func (self *_jsonFloat) UnmarshalJSON(data []byte) (err error) {
+	text := string(data)
+	if len(text) > 0 && text[0] == '"' {
+		if text, err = strconv.Unquote(text); err != nil {
+			return err
+		}
+		if text != "NaN" && text != "+Inf" && text != "-Inf" {
+			return fmt.Errorf("load json: %q is not a number", text)
+		}
+	}
+
+	val, err := strconv.ParseFloat(text, 64)
+	*self = _jsonFloat(val)
+
+	return err
+}
+
+//
+// Writes a vector as an array or a matrix as an array of rows,
+// one row per line
+//
+func SaveJSON(w io.Writer, data Data) (err error) {
+	rows, isVector, err := _saveRows("save json", data)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(w)
+	writeRow := func(row []float64) {
+		writer.WriteByte('[')
+		for col, val := range row {
+			if col > 0 {
+				writer.WriteString(", ")
+			}
+			text, _ := _jsonFloat(val).MarshalJSON()
+			writer.Write(text)
+		}
+		writer.WriteByte(']')
+	}
+
+	if isVector {
+		writeRow(rows[0])
 	} else {
-		bytes, err := ioutil.ReadFile(filePath)
-		rawData := strings.TrimSpace(string(bytes))
-
-		if err == nil {
-			// read the first line to introspect the column count
-			lines := strings.Split(rawData, "\n")
-			rows := len(lines)
-			cols := 0
-			for row, line := range lines {
-				data := strings.Split(line, ",")
-				if cols == 0 {
-					// first pass
-					cols = len(data)
-					matrix = NewMatrix(rows, cols)
-				}
-				for col, val := range data {
-					fval, _ := strconv.ParseFloat(val, 64)
-					matrix[row][col] = fval
-				}
+		writer.WriteByte('[')
+		for i, row := range rows {
+			if i > 0 {
+				writer.WriteByte(',')
 			}
-		} else {
-			log.Fatal(err)
+			writer.WriteString("\n  ")
+			writeRow(row)
 		}
+		writer.WriteString("\n]")
 	}
+	writer.WriteByte('\n')
 
-	return matrix
+	return writer.Flush()
+}
+
+//
+// Reads a vector from an array of numbers or a matrix
+// from an array of equal length rows
+//
+func LoadJSON(r io.Reader) (data Data, err error) {
+	var raw json.RawMessage
+	if err = json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("load json: %w", err)
+	}
+
+	// a matrix starts with the opening brackets of the array and its first row
+	if inner := bytes.TrimSpace(bytes.TrimPrefix(bytes.TrimSpace(raw), []byte("["))); len(inner) == 0 || inner[0] != '[' {
+		var values []_jsonFloat
+		if err = json.Unmarshal(raw, &values); err != nil {
+			return nil, fmt.Errorf("load json: %w", err)
+		}
+
+		vector := NewVector(len(values))
+		for i, val := range values {
+			vector[i] = float64(val)
+		}
+		return vector, nil
+	}
+
+	var rows [][]_jsonFloat
+	if err = json.Unmarshal(raw, &rows); err != nil {
+		return nil, fmt.Errorf("load json: %w", err)
+	}
+
+	matrix := NewEmptyMatrix(len(rows))
+	for i, row := range rows {
+		if len(row) != len(rows[0]) {
+			return nil, fmt.Errorf("load json: row %d: %w", i+1, ErrFieldCount)
+		}
+
+		matrix[i] = NewVector(len(row))
+		for j, val := range row {
+			matrix[i][j] = float64(val)
+		}
+	}
+
+	return matrix, nil
+}
+
+//
+// Variable names of the named matrix formats, which
+// must be valid Octave / MATLAB identifiers
+//
+var _varName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+//
+// Element types of the binary formats, which are
+// decoded to and encoded from float64
+//
+type _numType int
+
+const (
+	_float64 _numType = iota
+	_float32
+	_int8
+	_uint8
+	_int16
+	_uint16
+	_int32
+	_uint32
+	_int64
+	_uint64
+)
+
+var _numSizes = [...]int{8, 4, 1, 1, 2, 2, 4, 4, 8, 8}
+
+//
+// Size in bytes of an element
+//
+func (self _numType) size() int {
+	return _numSizes[self]
+}
+
+//
+// Decodes the elements packed in data
+//
+func _decodeNumbers(data []byte, typ _numType, order binary.ByteOrder) (values []float64) {
+	size := typ.size()
+	values = make([]float64, len(data)/size)
+
+	for i := range values {
+		b := data[i*size:]
+		switch typ {
+		case _float64:
+			values[i] = math.Float64frombits(order.Uint64(b))
+		case _float32:
+			values[i] = float64(math.Float32frombits(order.Uint32(b)))
+		case _int8:
+			values[i] = float64(int8(b[0]))
+		case _uint8:
+			values[i] = float64(b[0])
+		case _int16:
+			values[i] = float64(int16(order.Uint16(b)))
+		case _uint16:
+			values[i] = float64(order.Uint16(b))
+		case _int32:
+			values[i] = float64(int32(order.Uint32(b)))
+		case _uint32:
+			values[i] = float64(order.Uint32(b))
+		case _int64:
+			values[i] = float64(int64(order.Uint64(b)))
+		case _uint64:
+			values[i] = float64(order.Uint64(b))
+		}
+	}
+
+	return values
+}
+
+//
+// Packs values as elements of typ. Integer types round to the
+// nearest value and saturate at their range, NaN stores as 0.
+//
+func _encodeNumbers(values []float64, typ _numType, order binary.ByteOrder) (data []byte) {
+	size := typ.size()
+	data = make([]byte, len(values)*size)
+
+	for i, val := range values {
+		b := data[i*size:]
+		switch typ {
+		case _float64:
+			order.PutUint64(b, math.Float64bits(val))
+		case _float32:
+			order.PutUint32(b, math.Float32bits(float32(val)))
+		case _int8:
+			b[0] = byte(int8(_saturate(val, math.MinInt8, math.MaxInt8)))
+		case _uint8:
+			b[0] = byte(_saturate(val, 0, math.MaxUint8))
+		case _int16:
+			order.PutUint16(b, uint16(int16(_saturate(val, math.MinInt16, math.MaxInt16))))
+		case _uint16:
+			order.PutUint16(b, uint16(_saturate(val, 0, math.MaxUint16)))
+		case _int32:
+			order.PutUint32(b, uint32(int32(_saturate(val, math.MinInt32, math.MaxInt32))))
+		case _uint32:
+			order.PutUint32(b, uint32(_saturate(val, 0, math.MaxUint32)))
+		case _int64:
+			// MaxInt64 isn't representable, 2^63 rounds past it
+			v := _saturate(val, math.MinInt64, math.MaxInt64)
+			if v >= math.MaxInt64 {
+				order.PutUint64(b, math.MaxInt64)
+			} else {
+				order.PutUint64(b, uint64(int64(v)))
+			}
+		case _uint64:
+			v := _saturate(val, 0, math.MaxUint64)
+			if v >= math.MaxUint64 {
+				order.PutUint64(b, math.MaxUint64)
+			} else {
+				order.PutUint64(b, uint64(v))
+			}
+		}
+	}
+
+	return data
+}
+
+//
+// Rounds val to the nearest integer in [min, max], NaN as 0
+//
+func _saturate(val float64, min float64, max float64) float64 {
+	if math.IsNaN(val) {
+		return 0
+	}
+
+	return math.Max(min, math.Min(max, math.Round(val)))
 }
@@ -0,0 +1,206 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testSaveM = Matrix{{math.Pi, 0.1, -2}, {1e-300, math.Inf(1), math.NaN()}}
+
+//
+// Compares matrices element by element, NaN matching NaN
+//
+func _equalNaN(x Matrix, y Matrix) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for i, row := range x {
+		if len(row) != len(y[i]) {
+			return false
+		}
+		for j, val := range row {
+			if val != y[i][j] && !(math.IsNaN(val) && math.IsNaN(y[i][j])) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func TestSaveCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Save(&buf, testSaveM, FormatCSV); err != nil {
+		t.Fatal(err)
+	}
+
+	matrix, _, err := LoadCSV(&buf, nil)
+	if err != nil || !_equalNaN(matrix, testSaveM) {
+		t.Errorf("%v != %v (%v)", matrix, testSaveM, err)
+	}
+
+	buf.Reset()
+	SaveCSV(&buf, Vector{math.Pi, 2.0 / 3}, 3)
+	if buf.String() != "3.14,0.667\n" {
+		t.Errorf("%q vs expected %q", buf.String(), "3.14,0.667\n")
+	}
+}
+
+func TestSaveBinary(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Save(&buf, testSaveM, FormatBinary); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 4+3*4+6*8 {
+		t.Errorf("%d bytes vs expected %d", buf.Len(), 4+3*4+6*8)
+	}
+
+	data, err := LoadBinary(&buf)
+	if matrix, ok := data.(Matrix); err != nil || !ok || !_equalNaN(matrix, testSaveM) {
+		t.Errorf("%v != %v (%v)", data, testSaveM, err)
+	}
+
+	buf.Reset()
+	Save(&buf, Vector{1, 2, 3}, FormatBinary)
+	data, err = LoadBinary(&buf)
+	if err != nil || !Equal(data, Vector{1, 2, 3}) {
+		t.Errorf("%v != %v (%v)", data, Vector{1, 2, 3}, err)
+	}
+
+	_, err = LoadBinary(strings.NewReader("1,2,3\n"))
+	if !errors.Is(err, ErrBinaryFormat) {
+		t.Errorf("%v is not %v", err, ErrBinaryFormat)
+	}
+}
+
+func TestLoadBinaryBadShape(t *testing.T) {
+	// shapes far beyond the data must not be allocated up front
+	for _, shape := range [][]uint32{{2, 0xFFFFFFF0, 0xFFFFFFF0}, {2, 0xFFFFFFF0, 0}, {2, 4, 0x7FFFFFFF}, {1, 0xFFFFFFFF}} {
+		var buf bytes.Buffer
+		buf.Write(_binaryMagic)
+		binary.Write(&buf, binary.LittleEndian, shape)
+		buf.Write(_encodeNumbers([]float64{1, 2}, _float64, binary.LittleEndian))
+
+		if _, err := LoadBinary(&buf); err == nil {
+			t.Errorf("%v vs expected error", shape)
+		}
+	}
+}
+
+func TestSaveJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Save(&buf, testSaveM, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := LoadJSON(&buf)
+	if matrix, ok := data.(Matrix); err != nil || !ok || !_equalNaN(matrix, testSaveM) {
+		t.Errorf("%v != %v (%v)", data, testSaveM, err)
+	}
+
+	buf.Reset()
+	Save(&buf, Vector{0.5, -1}, FormatJSON)
+	if buf.String() != "[0.5, -1]\n" {
+		t.Errorf("%q vs expected %q", buf.String(), "[0.5, -1]\n")
+	}
+	data, err = LoadJSON(&buf)
+	if err != nil || !Equal(data, Vector{0.5, -1}) {
+		t.Errorf("%v != %v (%v)", data, Vector{0.5, -1}, err)
+	}
+
+	_, err = LoadJSON(strings.NewReader("[[1, 2], [3]]"))
+	if !errors.Is(err, ErrFieldCount) {
+		t.Errorf("%v is not %v", err, ErrFieldCount)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	load := map[Format]func(r io.Reader) (Data, error){
+		FormatCSV: func(r io.Reader) (Data, error) {
+			matrix, _, err := LoadCSV(r, nil)
+			return matrix, err
+		},
+		FormatBinary: LoadBinary,
+		FormatJSON:   LoadJSON,
+	}
+
+	tests := []struct {
+		name   string
+		data   Data
+		format Format
+		exp    Data
+	}{
+		{"csv matrix", Matrix{{1}, {2}}, FormatCSV, Matrix{{1}, {2}}},
+		{"csv vector", Vector{1, 2}, FormatCSV, Matrix{{1, 2}}},
+		{"csv empty", Matrix{}, FormatCSV, Matrix{}},
+		{"binary matrix", Matrix{{1}, {2}}, FormatBinary, Matrix{{1}, {2}}},
+		{"binary vector", Vector{1, 2}, FormatBinary, Vector{1, 2}},
+		{"binary no columns", Matrix{{}, {}}, FormatBinary, Matrix{{}, {}}},
+		{"binary empty", Matrix{}, FormatBinary, Matrix{}},
+		{"json matrix", Matrix{{1}, {2}}, FormatJSON, Matrix{{1}, {2}}},
+		{"json vector", Vector{1, 2}, FormatJSON, Vector{1, 2}},
+		{"json no columns", Matrix{{}, {}}, FormatJSON, Matrix{{}, {}}},
+		{"json empty", Matrix{}, FormatJSON, Vector{}},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := Save(&buf, test.data, test.format); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		// Equal doesn't take empty matrices, compare types and shapes too
+		data, err := load[test.format](&buf)
+		if err != nil || !reflect.DeepEqual(data, test.exp) {
+			t.Errorf("%s: %#v != %#v (%v)", test.name, data, test.exp, err)
+		}
+	}
+
+	if err := Save(&bytes.Buffer{}, Matrix{{}, {}}, FormatCSV); err == nil {
+		t.Errorf("csv without columns vs expected error")
+	}
+
+	// a ragged matrix has no single shape to save
+	for _, format := range []Format{FormatCSV, FormatBinary, FormatJSON} {
+		var dimErr *DimensionError
+		if err := Save(&bytes.Buffer{}, Matrix{{1, 2}, {3}}, format); !errors.As(err, &dimErr) {
+			t.Errorf("format %d: %v vs expected a dimension error", format, err)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	theta, _ := GradientDescent(Matrix{{1, 1}, {1, 2}, {1, 3}}, Matrix{{2}, {3}, {4}}, NewMatrix(2, 1), 0.1, 100)
+
+	filePath := filepath.Join(t.TempDir(), "theta.csv")
+	file, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Save(file, theta, FormatCSV)
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if loaded := Load(filePath); !Equal(loaded, theta) {
+		t.Errorf("%v != %v", loaded, theta)
+	}
+
+	if err := Save(&bytes.Buffer{}, 1.0, FormatCSV); err == nil {
+		t.Errorf("scalar vs expected error")
+	}
+}
@@ -0,0 +1,862 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"fmt"
+	. "golog"
+	"math"
+	"reflect"
+	"sort"
+)
+
+//
+// Relative spacing of float64 values near 1.0
+//
+const _eps float64 = 2.220446049250313e-16
+
+//
+// Creates a copy of a matrix.
+//
+func _copyM(matrix Matrix) (result Matrix) {
+	rows, cols := Size(matrix)
+	result = NewMatrix(rows, cols)
+
+	for i, row := range matrix {
+		copy(result[i], row)
+	}
+
+	return result
+}
+
+//
+// Transpose of a matrix which, unlike Transpose, always
+// returns a matrix, even for a single row or column.
+//
+func _transposeM(matrix Matrix) (result Matrix) {
+	rows, cols := Size(matrix)
+	result = NewMatrix(cols, rows)
+
+	for i, row := range matrix {
+		for j, val := range row {
+			result[j][i] = val
+		}
+	}
+
+	return result
+}
+
+//
+// Largest absolute element of a matrix
+//
+func _maxAbsM(matrix Matrix) (result float64) {
+	for _, row := range matrix {
+		for _, val := range row {
+			if math.Abs(val) > result {
+				result = math.Abs(val)
+			}
+		}
+	}
+
+	return result
+}
+
+//
+// Normalizes a right hand side argument into a matrix with one
+// column per system. A vector is treated as a single column.
+//
+func _columns(op string, b Data) (matrix Matrix, isVector bool, err error) {
+	b, _ = _undense(b)
+
+	switch b.(type) {
+	case Matrix:
+		matrix = b.(Matrix)
+	case Vector:
+		matrix = _transposeM(Matrix{b.(Vector)})
+		isVector = true
+	default:
+		err = &TypeError{Op: op, Type1: reflect.TypeOf(b)}
+	}
+
+	return matrix, isVector, err
+}
+
+//
+// Converts a solution with one column per system back to the kind of
+// the right hand side argument it was solved for.
+//
+func _uncolumns(matrix Matrix, isVector bool, isDense bool) (data Data) {
+	switch {
+	case isVector && isDense:
+		data = DenseFromVector(_transposeM(matrix)[0])
+	case isVector:
+		data = Vector(_transposeM(matrix)[0])
+	case isDense:
+		data = DenseFromMatrix(matrix)
+	default:
+		data = matrix
+	}
+
+	return data
+}
+
+//
+// Internal: LU factorization with partial pivoting computed in a single
+// matrix, L below the diagonal (unit diagonal implied) and U on and above
+// it. Row i of the factored matrix is row pivot[i] of A and sign is the
+// parity of the row exchanges. A pivot no larger than the rounding error
+// of the elimination flags the matrix as singular.
+//
+func _lu(A Matrix) (lu Matrix, pivot []int, sign float64, singular bool) {
+	n := len(A)
+	lu = _copyM(A)
+	pivot = make([]int, n)
+	for i := range pivot {
+		pivot[i] = i
+	}
+	sign = 1.0
+
+	tol := float64(n) * _eps * _maxAbsM(A)
+
+	for k := 0; k < n; k++ {
+		// bring the largest remaining value in column k onto the diagonal
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(lu[i][k]) > math.Abs(lu[p][k]) {
+				p = i
+			}
+		}
+		if p != k {
+			lu[p], lu[k] = lu[k], lu[p]
+			pivot[p], pivot[k] = pivot[k], pivot[p]
+			sign = -sign
+		}
+
+		if math.Abs(lu[k][k]) <= tol {
+			singular = true
+			if lu[k][k] == 0 {
+				continue
+			}
+		}
+
+		for i := k + 1; i < n; i++ {
+			lu[i][k] /= lu[k][k]
+			factor := lu[i][k]
+			if factor == 0 {
+				continue
+			}
+			for j := k + 1; j < n; j++ {
+				lu[i][j] -= factor * lu[k][j]
+			}
+		}
+	}
+
+	return lu, pivot, sign, singular
+}
+
+//
+// Internal: solves LU x = P b in place for each column of b.
+//
+func _luSolve(lu Matrix, pivot []int, b Matrix) (x Matrix) {
+	n := len(lu)
+	_, cols := Size(b)
+	x = NewMatrix(n, cols)
+
+	for i, p := range pivot {
+		copy(x[i], b[p])
+	}
+
+	// forward substitution with the unit lower triangle
+	for k := 0; k < n; k++ {
+		for i := k + 1; i < n; i++ {
+			factor := lu[i][k]
+			for j := 0; j < cols; j++ {
+				x[i][j] -= factor * x[k][j]
+			}
+		}
+	}
+
+	// back substitution with the upper triangle
+	for k := n - 1; k >= 0; k-- {
+		for j := 0; j < cols; j++ {
+			x[k][j] /= lu[k][k]
+		}
+		for i := 0; i < k; i++ {
+			factor := lu[i][k]
+			for j := 0; j < cols; j++ {
+				x[i][j] -= factor * x[k][j]
+			}
+		}
+	}
+
+	return x
+}
+
+//
+// LU factorization with partial pivoting such that P * A = L * U,
+// where L is unit lower triangular, U upper triangular and P a
+// permutation matrix. Octave: [L, U, P] = lu(A)
+//
+func LU(A Matrix) (L Matrix, U Matrix, P Matrix, err error) {
+	rows, cols := Size(A)
+	if rows != cols {
+		return nil, nil, nil, fmt.Errorf("lu: %w (op1 is %dx%d)", ErrNotSquare, rows, cols)
+	}
+
+	lu, pivot, _, _ := _lu(A)
+
+	L = Eye(rows)
+	U = NewMatrix(rows, cols)
+	P = NewMatrix(rows, cols)
+	for i, row := range lu {
+		for j, val := range row {
+			if j < i {
+				L[i][j] = val
+			} else {
+				U[i][j] = val
+			}
+		}
+		P[i][pivot[i]] = 1
+	}
+
+	return L, U, P, nil
+}
+
+//
+// Solves the linear system A * x = b for a square A. The b argument may
+// be a vector, treated as a column, or a matrix with one system per
+// column, and x is returned as the same kind. Octave: x = A \ b
+//
+func Solve(A Data, b Data) (x Data, err error) {
+	A, isDense := _undense(A)
+	matrix, ok := A.(Matrix)
+	if !ok {
+		return nil, &TypeError{Op: "\\", Type1: reflect.TypeOf(A), Type2: reflect.TypeOf(b)}
+	}
+
+	rows, cols := Size(matrix)
+	if rows != cols {
+		return nil, fmt.Errorf("operator \\: %w (op1 is %dx%d)", ErrNotSquare, rows, cols)
+	}
+
+	_, bIsDense := b.(*Dense)
+	rhs, isVector, err := _columns("\\", b)
+	if err != nil {
+		return nil, err
+	}
+	if len(rhs) != rows {
+		return nil, _dimensionError("\\", matrix, b)
+	}
+
+	lu, pivot, _, singular := _lu(matrix)
+	if singular {
+		return nil, fmt.Errorf("operator \\: %w", ErrSingular)
+	}
+
+	x = _uncolumns(_luSolve(lu, pivot, rhs), isVector, isDense || bIsDense)
+
+	return x, nil
+}
+
+//
+// Determinant of a square matrix. Octave: det(A)
+//
+func Det(A Matrix) (det float64, err error) {
+	rows, cols := Size(A)
+	if rows != cols {
+		return 0, fmt.Errorf("det: %w (op1 is %dx%d)", ErrNotSquare, rows, cols)
+	}
+
+	lu, _, sign, _ := _lu(A)
+
+	det = sign
+	for i, row := range lu {
+		det *= row[i]
+	}
+
+	return det, nil
+}
+
+//
+// Inverse of a square matrix. Octave: inv(A)
+//
+func Inv(A Matrix) (inv Matrix, err error) {
+	rows, cols := Size(A)
+	if rows != cols {
+		return nil, fmt.Errorf("inverse: %w (op1 is %dx%d)", ErrNotSquare, rows, cols)
+	}
+
+	lu, pivot, _, singular := _lu(A)
+	if singular {
+		return nil, fmt.Errorf("inverse: %w", ErrSingular)
+	}
+
+	inv = _luSolve(lu, pivot, Eye(rows))
+
+	return inv, nil
+}
+
+//
+// Internal: Householder QR factorization of an m x n matrix, optionally
+// with column pivoting. R is returned m x n, upper trapezoidal in its
+// first min(m, n) rows, together with the reflectors H_k = I - 2vv'/v'v
+// such that H_(k-1) * ... * H_0 * A[:, perm] = R.
+//
+func _householder(A Matrix, pivoting bool) (R Matrix, vs []Vector, vnorms Vector, perm []int) {
+	m, n := Size(A)
+	R = _copyM(A)
+	steps := m
+	if n < m {
+		steps = n
+	}
+	vs = make([]Vector, steps)
+	vnorms = NewVector(steps)
+	perm = make([]int, n)
+	for j := range perm {
+		perm[j] = j
+	}
+
+	for k := 0; k < steps; k++ {
+		if pivoting {
+			// move the column with the largest remaining norm into position k
+			best, bestNorm := k, -1.0
+			for j := k; j < n; j++ {
+				norm := 0.0
+				for i := k; i < m; i++ {
+					norm += R[i][j] * R[i][j]
+				}
+				if norm > bestNorm {
+					best, bestNorm = j, norm
+				}
+			}
+			if best != k {
+				for i := range R {
+					R[i][k], R[i][best] = R[i][best], R[i][k]
+				}
+				perm[k], perm[best] = perm[best], perm[k]
+			}
+		}
+
+		// reflect R[k:, k] onto -sign(x0) * ||x|| * e1, avoiding cancellation
+		v := NewVector(m - k)
+		norm := 0.0
+		for i := range v {
+			v[i] = R[k+i][k]
+			norm += v[i] * v[i]
+		}
+		norm = math.Sqrt(norm)
+		if norm == 0 {
+			vs[k] = v
+			continue
+		}
+		if v[0] > 0 {
+			norm = -norm
+		}
+		v[0] -= norm
+
+		vnorm := 0.0
+		for _, val := range v {
+			vnorm += val * val
+		}
+		vs[k] = v
+		vnorms[k] = vnorm
+
+		_reflect(R, v, vnorm, k, k)
+		R[k][k] = norm
+		for i := k + 1; i < m; i++ {
+			R[i][k] = 0
+		}
+	}
+
+	return R, vs, vnorms, perm
+}
+
+//
+// Internal: applies the reflector I - 2vv'/v'v to rows k and below of
+// columns col and after of a matrix, in place.
+//
+func _reflect(matrix Matrix, v Vector, vnorm float64, k int, col int) {
+	if vnorm == 0 {
+		return
+	}
+
+	_, cols := Size(matrix)
+	for j := col; j < cols; j++ {
+		s := 0.0
+		for i, val := range v {
+			s += val * matrix[k+i][j]
+		}
+		s = 2 * s / vnorm
+		for i, val := range v {
+			matrix[k+i][j] -= s * val
+		}
+	}
+}
+
+//
+// Householder QR factorization, A = Q * R. For an m x n matrix with
+// k = min(m, n), Q is m x k with orthonormal columns and R is k x n
+// upper triangular. Octave: [Q, R] = qr(A, 0)
+//
+func QR(A Matrix) (Q Matrix, R Matrix) {
+	m, n := Size(A)
+	full, vs, vnorms, _ := _householder(A, false)
+	k := len(vs)
+
+	// accumulate Q = H_0 * ... * H_(k-1) applied to the first k columns of I
+	Q = NewMatrix(m, k)
+	for i := 0; i < k; i++ {
+		Q[i][i] = 1
+	}
+	for i := k - 1; i >= 0; i-- {
+		_reflect(Q, vs[i], vnorms[i], i, 0)
+	}
+
+	R = NewMatrix(k, n)
+	for i := range R {
+		copy(R[i], full[i])
+	}
+
+	return Q, R
+}
+
+//
+// Least squares solution of A * x = b for an m x n matrix A of any shape,
+// minimizing the 2-norm of the residual with a column pivoted Householder
+// QR. The b argument may be a vector, treated as a column, or a matrix
+// with one system per column, and x is returned as the same kind. For
+// each system residuals holds the sum of squared residuals. When A is
+// rank deficient the basic solution with rank non-zero entries is
+// returned. Octave: x = A \ b
+//
+func LstSq(A Matrix, b Data) (x Data, residuals Vector, rank int, err error) {
+	m, n := Size(A)
+
+	_, bIsDense := b.(*Dense)
+	rhs, isVector, err := _columns("\\", b)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(rhs) != m {
+		return nil, nil, 0, _dimensionError("\\", A, b)
+	}
+	_, k := Size(rhs)
+
+	R, vs, vnorms, perm := _householder(A, true)
+
+	// rank from the pivoted diagonal of R, which is non-increasing
+	if len(vs) > 0 {
+		tol := float64(m)
+		if n > m {
+			tol = float64(n)
+		}
+		tol *= _eps * math.Abs(R[0][0])
+		for rank < len(vs) && math.Abs(R[rank][rank]) > tol {
+			rank++
+		}
+	}
+
+	// c = Q' * b
+	c := _copyM(rhs)
+	for i := range vs {
+		_reflect(c, vs[i], vnorms[i], i, 0)
+	}
+
+	// back substitution with the leading rank x rank block of R
+	solution := NewMatrix(n, k)
+	for j := 0; j < k; j++ {
+		for i := rank - 1; i >= 0; i-- {
+			s := c[i][j]
+			for l := i + 1; l < rank; l++ {
+				s -= R[i][l] * solution[perm[l]][j]
+			}
+			solution[perm[i]][j] = s / R[i][i]
+		}
+	}
+
+	residuals = NewVector(k)
+	for j := 0; j < k; j++ {
+		for i := rank; i < m; i++ {
+			residuals[j] += c[i][j] * c[i][j]
+		}
+	}
+
+	x = _uncolumns(solution, isVector, bIsDense)
+
+	return x, residuals, rank, nil
+}
+
+//
+// Maximum number of Jacobi sweeps before EigSym and SVD give up
+// on further convergence
+//
+const _jacobiSweeps = 100
+
+//
+// Internal: reorders values, and the matching columns of each
+// matrix, ascending or descending by value.
+//
+func _sortColumns(values Vector, descending bool, matrices ...Matrix) {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i int, j int) bool {
+		if descending {
+			return values[order[i]] > values[order[j]]
+		}
+		return values[order[i]] < values[order[j]]
+	})
+
+	sorted := NewVector(len(values))
+	for i, o := range order {
+		sorted[i] = values[o]
+	}
+	copy(values, sorted)
+
+	for _, matrix := range matrices {
+		for _, row := range matrix {
+			copy(sorted, row)
+			for i, o := range order {
+				row[i] = sorted[o]
+			}
+		}
+	}
+}
+
+//
+// Internal: Jacobi rotation (c, s) that annihilates the off-diagonal
+// term of the symmetric 2x2 system [[app, apq], [apq, aqq]]
+//
+func _jacobiRotation(app float64, aqq float64, apq float64) (c float64, s float64) {
+	theta := (aqq - app) / (2 * apq)
+	t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+	if theta < 0 {
+		t = -t
+	}
+	c = 1 / math.Sqrt(t*t+1)
+	s = t * c
+
+	return c, s
+}
+
+//
+// Internal: rotates columns p and q of a matrix by (c, s) in place
+//
+func _rotateColumns(matrix Matrix, p int, q int, c float64, s float64) {
+	for _, row := range matrix {
+		vp, vq := row[p], row[q]
+		row[p] = c*vp - s*vq
+		row[q] = s*vp + c*vq
+	}
+}
+
+//
+// Eigenvalues and eigenvectors of a symmetric matrix by the cyclic
+// Jacobi method. Values are in ascending order and column i of vectors
+// is the unit eigenvector for values[i]. Only the symmetric part
+// (A + A') / 2 of the matrix is considered. Octave: [V, D] = eig(A)
+//
+func EigSym(A Matrix) (values Vector, vectors Matrix) {
+	n, cols := Size(A)
+	if n != cols {
+		LogErrorf("error: eig: %s (op1 is %dx%d)\n", ErrNotSquare, n, cols)
+		return nil, nil
+	}
+
+	a := NewMatrix(n, n)
+	for i := range a {
+		for j := range a[i] {
+			a[i][j] = (A[i][j] + A[j][i]) / 2
+		}
+	}
+	vectors = Eye(n)
+
+	for sweep := 0; sweep < _jacobiSweeps; sweep++ {
+		off, total := 0.0, 0.0
+		for i, row := range a {
+			for j, val := range row {
+				if i != j {
+					off += val * val
+				}
+				total += val * val
+			}
+		}
+		if off <= _eps*_eps*total {
+			break
+		}
+
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				c, s := _jacobiRotation(a[p][p], a[q][q], a[p][q])
+
+				// a = J' * a * J
+				_rotateColumns(a, p, q, c, s)
+				for k := 0; k < n; k++ {
+					vp, vq := a[p][k], a[q][k]
+					a[p][k] = c*vp - s*vq
+					a[q][k] = s*vp + c*vq
+				}
+				_rotateColumns(vectors, p, q, c, s)
+			}
+		}
+	}
+
+	values = NewVector(n)
+	for i := range values {
+		values[i] = a[i][i]
+	}
+	_sortColumns(values, false, vectors)
+
+	return values, vectors
+}
+
+//
+// Thin singular value decomposition, A = U * diag(S) * V', by one-sided
+// (Hestenes) Jacobi rotations. For an m x n matrix with k = min(m, n),
+// U is m x k, S holds the k singular values in descending order and V is
+// n x k. Octave: [U, S, V] = svd(A, 0)
+//
+func SVD(A Matrix) (U Matrix, S Vector, V Matrix) {
+	m, n := Size(A)
+	if m < n {
+		// orthogonalize the shorter side, A' = V * diag(S) * U'
+		V, S, U = SVD(_transposeM(A))
+		return U, S, V
+	}
+
+	U = _copyM(A)
+	V = Eye(n)
+
+	for sweep := 0; sweep < _jacobiSweeps; sweep++ {
+		rotated := false
+		for p := 0; p < n-1; p++ {
+			for q := p + 1; q < n; q++ {
+				alpha, beta, gamma := 0.0, 0.0, 0.0
+				for _, row := range U {
+					alpha += row[p] * row[p]
+					beta += row[q] * row[q]
+					gamma += row[p] * row[q]
+				}
+				if gamma == 0 || math.Abs(gamma) <= _eps*math.Sqrt(alpha*beta) {
+					continue
+				}
+				rotated = true
+
+				c, s := _jacobiRotation(alpha, beta, gamma)
+				_rotateColumns(U, p, q, c, s)
+				_rotateColumns(V, p, q, c, s)
+			}
+		}
+		if !rotated {
+			break
+		}
+	}
+
+	// the columns are now orthogonal, their norms are the singular values
+	S = NewVector(n)
+	for j := range S {
+		for _, row := range U {
+			S[j] += row[j] * row[j]
+		}
+		S[j] = math.Sqrt(S[j])
+		if S[j] > 0 {
+			for _, row := range U {
+				row[j] /= S[j]
+			}
+		}
+	}
+	_sortColumns(S, true, U, V)
+
+	return U, S, V
+}
+
+//
+// Internal: singular values at or below this are treated as zero by
+// Pinv and Rank, matching the Octave default max(size(A)) * s(1) * eps
+//
+func _svdTolerance(A Matrix, S Vector) (tol float64) {
+	m, n := Size(A)
+	if len(S) > 0 {
+		if n > m {
+			m = n
+		}
+		tol = float64(m) * S[0] * _eps
+	}
+
+	return tol
+}
+
+//
+// Moore-Penrose pseudo-inverse, V * diag(1 / S) * U' over the
+// non-negligible singular values. Octave: pinv(A)
+//
+func Pinv(A Matrix) (pinv Matrix) {
+	m, n := Size(A)
+	U, S, V := SVD(A)
+	tol := _svdTolerance(A, S)
+
+	pinv = NewMatrix(n, m)
+	for k, s := range S {
+		if s <= tol {
+			break
+		}
+		for i := 0; i < n; i++ {
+			vs := V[i][k] / s
+			if vs == 0 {
+				continue
+			}
+			for j := 0; j < m; j++ {
+				pinv[i][j] += vs * U[j][k]
+			}
+		}
+	}
+
+	return pinv
+}
+
+//
+// Number of non-negligible singular values. Octave: rank(A)
+//
+func Rank(A Matrix) (rank int) {
+	_, S, _ := SVD(A)
+	tol := _svdTolerance(A, S)
+
+	for _, s := range S {
+		if s > tol {
+			rank++
+		}
+	}
+
+	return rank
+}
+
+//
+// 2-norm condition number, the ratio of the largest to the smallest
+// singular value, +Inf for a singular matrix. Octave: cond(A)
+//
+func Cond(A Matrix) (cond float64) {
+	_, S, _ := SVD(A)
+	if len(S) == 0 {
+		return 0
+	}
+
+	last := S[len(S)-1]
+	if last == 0 {
+		return math.Inf(1)
+	}
+	cond = S[0] / last
+
+	return cond
+}
+
+//
+// Cholesky factorization of a symmetric positive definite matrix,
+// A = L * L' with L lower triangular. Octave: L = chol(A, "lower")
+//
+func Cholesky(A Matrix) (L Matrix, err error) {
+	n, cols := Size(A)
+	if n != cols {
+		return nil, fmt.Errorf("chol: %w (op1 is %dx%d)", ErrNotSquare, n, cols)
+	}
+
+	tol := float64(n) * _eps * _maxAbsM(A)
+	for i := 0; i < n; i++ {
+		for j := 0; j < i; j++ {
+			if math.Abs(A[i][j]-A[j][i]) > tol {
+				return nil, fmt.Errorf("chol: %w (not symmetric at %d,%d)", ErrNotPositiveDefinite, i+1, j+1)
+			}
+		}
+	}
+
+	L = NewMatrix(n, n)
+	for j := 0; j < n; j++ {
+		d := A[j][j]
+		for k := 0; k < j; k++ {
+			d -= L[j][k] * L[j][k]
+		}
+		if d <= 0 || math.IsNaN(d) {
+			return nil, fmt.Errorf("chol: %w (leading minor %d is not positive)", ErrNotPositiveDefinite, j+1)
+		}
+		L[j][j] = math.Sqrt(d)
+
+		for i := j + 1; i < n; i++ {
+			s := A[i][j]
+			for k := 0; k < j; k++ {
+				s -= L[i][k] * L[j][k]
+			}
+			L[i][j] = s / L[j][j]
+		}
+	}
+
+	return L, nil
+}
+
+//
+// Solves A * x = b given the Cholesky factor L of A, by forward
+// substitution with L and back substitution with L'. The b argument may
+// be a vector, treated as a column, or a matrix with one system per
+// column, and x is returned as the same kind.
+//
+func CholeskySolve(L Matrix, b Data) (x Data, err error) {
+	n, cols := Size(L)
+	if n != cols {
+		return nil, fmt.Errorf("chol: %w (op1 is %dx%d)", ErrNotSquare, n, cols)
+	}
+
+	_, bIsDense := b.(*Dense)
+	rhs, isVector, err := _columns("\\", b)
+	if err != nil {
+		return nil, err
+	}
+	if len(rhs) != n {
+		return nil, _dimensionError("\\", L, b)
+	}
+	_, k := Size(rhs)
+
+	y := _copyM(rhs)
+	for j := 0; j < k; j++ {
+		// L * y = b
+		for i := 0; i < n; i++ {
+			s := y[i][j]
+			for l := 0; l < i; l++ {
+				s -= L[i][l] * y[l][j]
+			}
+			y[i][j] = s / L[i][i]
+		}
+		// L' * x = y
+		for i := n - 1; i >= 0; i-- {
+			s := y[i][j]
+			for l := i + 1; l < n; l++ {
+				s -= L[l][i] * y[l][j]
+			}
+			y[i][j] = s / L[i][i]
+		}
+	}
+
+	x = _uncolumns(y, isVector, bIsDense)
+
+	return x, nil
+}
+
+//
+// Log of the determinant of a symmetric positive definite matrix,
+// 2 * sum(log(diag(L))), which stays finite where Det would overflow
+//
+func LogDetSPD(A Matrix) (logdet float64, err error) {
+	L, err := Cholesky(A)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, row := range L {
+		logdet += 2 * math.Log(row[i])
+	}
+
+	return logdet, nil
+}
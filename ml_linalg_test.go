@@ -0,0 +1,324 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+//
+// Rounds every element of a vector or matrix to p decimals so
+// results can be compared exactly with Equal
+//
+func _roundData(data Data, p int) (result Data) {
+	switch data.(type) {
+	case Matrix:
+		rows, cols := Size(data)
+		matrix := NewMatrix(rows, cols)
+		for i, row := range data.(Matrix) {
+			for j, val := range row {
+				matrix[i][j] = Round(val, p)
+			}
+		}
+		result = matrix
+	case Vector:
+		vector := NewVector(len(data.(Vector)))
+		for i, val := range data.(Vector) {
+			vector[i] = Round(val, p)
+		}
+		result = vector
+	}
+
+	return result
+}
+
+func TestLU(t *testing.T) {
+	A := Matrix{{1, 2, 0}, {3, 4, 4}, {5, 6, 3}}
+	L, U, P, err := LU(A)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	PA := Mul(P, A)
+	LU := Mul(L, U)
+	if !Equal(_roundData(PA, 6), _roundData(LU, 6)) {
+		t.Errorf("%v != %v", PA, LU)
+	}
+
+	for i := range U {
+		if L[i][i] != 1 {
+			t.Errorf("L[%d][%d] %f vs expected 1", i, i, L[i][i])
+		}
+		for j := 0; j < i; j++ {
+			if U[i][j] != 0 || L[j][i] != 0 {
+				t.Errorf("L / U not triangular at %d,%d", i, j)
+			}
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+	A := Matrix{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}}
+
+	x, err := Solve(A, Vector{8, -11, -3})
+	exp := Vector{2, 3, -1}
+	if err != nil || !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v (%v)", x, exp, err)
+	}
+
+	X, err := Solve(A, Matrix{{8, 1}, {-11, -1}, {-3, 2}})
+	EXP := Matrix{{2, -1}, {3, 2}, {-1, -1}}
+	if err != nil || !Equal(_roundData(X, 6), EXP) {
+		t.Errorf("%v != %v (%v)", X, EXP, err)
+	}
+}
+
+func TestSolveSingular(t *testing.T) {
+	_, err := Solve(Matrix{{1, 2}, {2, 4}}, Vector{1, 2})
+	if !errors.Is(err, ErrSingular) {
+		t.Errorf("%v is not %v", err, ErrSingular)
+	}
+}
+
+func TestSolveNonconformant(t *testing.T) {
+	_, err := Solve(Matrix{{1, 2}, {3, 4}}, Vector{1, 2, 3})
+	var dimErr *DimensionError
+	if !errors.As(err, &dimErr) {
+		t.Errorf("%v is not a DimensionError", err)
+	}
+}
+
+func TestDet(t *testing.T) {
+	det, err := Det(Matrix{{2, 1, -1}, {-3, -1, 2}, {-2, 1, 2}})
+	if err != nil || Round(det, 6) != -1.0 {
+		t.Errorf("%f vs expected %f (%v)", det, -1.0, err)
+	}
+
+	det, err = Det(Matrix{{1, 2}, {2, 4}})
+	if err != nil || det != 0 {
+		t.Errorf("%f vs expected %f (%v)", det, 0.0, err)
+	}
+
+	_, err = Det(Matrix{{1, 2, 3}, {4, 5, 6}})
+	if !errors.Is(err, ErrNotSquare) {
+		t.Errorf("%v is not %v", err, ErrNotSquare)
+	}
+}
+
+func TestInv(t *testing.T) {
+	x, err := Inv(Matrix{{4, 7}, {2, 6}})
+	exp := Matrix{{0.6, -0.7}, {-0.2, 0.4}}
+	if err != nil || !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v (%v)", x, exp, err)
+	}
+
+	_, err = Inv(Matrix{{1, 2}, {2, 4}})
+	if !errors.Is(err, ErrSingular) {
+		t.Errorf("%v is not %v", err, ErrSingular)
+	}
+}
+
+func TestDivVM(t *testing.T) {
+	// x * A = v
+	x := Div(Vector{5, 6}, Matrix{{1, 2}, {3, 4}})
+	exp := Vector{-1, 2}
+	if !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v", x, exp)
+	}
+}
+
+func TestQR(t *testing.T) {
+	A := Matrix{{12, -51, 4}, {6, 167, -68}, {-4, 24, -41}, {1, 2, 3}}
+	Q, R := QR(A)
+
+	if rows, cols := Size(Q); rows != 4 || cols != 3 {
+		t.Fatalf("Q is %dx%d vs expected 4x3", rows, cols)
+	}
+	if rows, cols := Size(R); rows != 3 || cols != 3 {
+		t.Fatalf("R is %dx%d vs expected 3x3", rows, cols)
+	}
+
+	QR := Mul(Q, R)
+	if !Equal(_roundData(QR, 6), A) {
+		t.Errorf("%v != %v", QR, A)
+	}
+
+	QtQ := Mul(_transposeM(Q), Q)
+	if !Equal(_roundData(QtQ, 6), Eye(3)) {
+		t.Errorf("%v is not orthonormal", Q)
+	}
+
+	for i := range R {
+		for j := 0; j < i; j++ {
+			if R[i][j] != 0 {
+				t.Errorf("R not upper triangular at %d,%d", i, j)
+			}
+		}
+	}
+}
+
+func TestLstSq(t *testing.T) {
+	A := Matrix{{1, 1}, {1, 2}, {1, 3}, {1, 4}}
+	x, residuals, rank, err := LstSq(A, Matrix{{6}, {5}, {7}, {10}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Matrix{{3.5}, {1.4}}
+	if !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v", x, exp)
+	}
+	if rank != 2 {
+		t.Errorf("rank %d vs expected %d", rank, 2)
+	}
+	_testA(t, residuals[0], 4.2)
+}
+
+func TestLstSqRankDeficient(t *testing.T) {
+	A := Matrix{{1, 2}, {2, 4}, {3, 6}}
+	x, residuals, rank, err := LstSq(A, Vector{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rank != 1 {
+		t.Errorf("rank %d vs expected %d", rank, 1)
+	}
+	_testA(t, residuals[0], 0)
+
+	Ax := Mul(x, _transposeM(A))
+	if !Equal(_roundData(Ax, 6), Vector{1, 2, 3}) {
+		t.Errorf("%v != %v", Ax, Vector{1, 2, 3})
+	}
+}
+
+func TestLstSqLoad(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "data.txt")
+	err := os.WriteFile(filePath, []byte("1,6\n2,5\n3,7\n4,10\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := Load(filePath)
+	X := Join(Ones(4, 1), Cols(data, 1, 1)).(Matrix)
+	y := Cols(data, 2, 2)
+
+	x, _, _, err := LstSq(X, y)
+	exp := Matrix{{3.5}, {1.4}}
+	if err != nil || !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v (%v)", x, exp, err)
+	}
+}
+
+func TestEigSym(t *testing.T) {
+	A := Matrix{{2, -1, 0}, {-1, 2, -1}, {0, -1, 2}}
+	values, vectors := EigSym(A)
+
+	exp := Vector{2 - math.Sqrt2, 2, 2 + math.Sqrt2}
+	if !Equal(_roundData(values, 6), _roundData(exp, 6)) {
+		t.Errorf("%v != %v", values, exp)
+	}
+
+	// A * V = V * diag(values)
+	AV := Mul(A, vectors).(Matrix)
+	for i, row := range AV {
+		for j, val := range row {
+			if Round(val, 6) != Round(vectors[i][j]*values[j], 6) {
+				t.Errorf("A * v != lambda * v for eigenvalue %f", values[j])
+			}
+		}
+	}
+}
+
+func TestSVD(t *testing.T) {
+	A := Matrix{{3, 2, 2}, {2, 3, -2}}
+	U, S, V := SVD(A)
+
+	exp := Vector{5, 3}
+	if !Equal(_roundData(S, 6), exp) {
+		t.Errorf("%v != %v", S, exp)
+	}
+
+	if rows, cols := Size(U); rows != 2 || cols != 2 {
+		t.Errorf("U is %dx%d vs expected 2x2", rows, cols)
+	}
+	if rows, cols := Size(V); rows != 3 || cols != 2 {
+		t.Errorf("V is %dx%d vs expected 3x2", rows, cols)
+	}
+
+	USV := Mul(Mul(U, Matrix{{S[0], 0}, {0, S[1]}}), _transposeM(V))
+	if !Equal(_roundData(USV, 6), A) {
+		t.Errorf("%v != %v", USV, A)
+	}
+}
+
+func TestPinv(t *testing.T) {
+	A := Matrix{{1, 2}, {3, 4}, {5, 6}}
+	x := Pinv(A)
+	exp := Matrix{{-1.333333, -0.333333, 0.666667}, {1.083333, 0.333333, -0.416667}}
+	if !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v", x, exp)
+	}
+}
+
+func TestRank(t *testing.T) {
+	if r := Rank(Matrix{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}); r != 2 {
+		t.Errorf("rank %d vs expected %d", r, 2)
+	}
+	if r := Rank(Eye(4)); r != 4 {
+		t.Errorf("rank %d vs expected %d", r, 4)
+	}
+}
+
+func TestCond(t *testing.T) {
+	_testA(t, Cond(Matrix{{1, 0}, {0, 4}}), 4)
+	_testA(t, Cond(Matrix{{3, 2, 2}, {2, 3, -2}}), 1.666667)
+}
+
+func TestCholesky(t *testing.T) {
+	A := Matrix{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}}
+	L, err := Cholesky(A)
+	exp := Matrix{{2, 0, 0}, {6, 1, 0}, {-8, 5, 3}}
+	if err != nil || !Equal(_roundData(L, 6), exp) {
+		t.Errorf("%v != %v (%v)", L, exp, err)
+	}
+
+	_, err = Cholesky(Matrix{{1, 2}, {2, 1}})
+	if !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("%v is not %v", err, ErrNotPositiveDefinite)
+	}
+
+	_, err = Cholesky(Matrix{{2, 1}, {0, 2}})
+	if !errors.Is(err, ErrNotPositiveDefinite) {
+		t.Errorf("%v is not %v", err, ErrNotPositiveDefinite)
+	}
+}
+
+func TestCholeskySolve(t *testing.T) {
+	A := Matrix{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}}
+	L, _ := Cholesky(A)
+
+	x, err := CholeskySolve(L, Vector{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Ax := Mul(x, A)
+	if !Equal(_roundData(Ax, 6), Vector{1, 2, 3}) {
+		t.Errorf("%v != %v", Ax, Vector{1, 2, 3})
+	}
+}
+
+func TestLogDetSPD(t *testing.T) {
+	A := Matrix{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}}
+	logdet, err := LogDetSPD(A)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_testA(t, logdet, Round(math.Log(36), 6))
+}
@@ -0,0 +1,59 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	. "golog"
+)
+
+//
+// Linear regression model trained by gradient descent, with the GD
+// optimizer at rate Alpha unless another Optimizer is set. As with
+// ComputeLRCost, X is expected to carry its own bias column of 1's
+// and y is a single column matrix.
+//
+type LinearRegression struct {
+	Alpha     float64   // learning rate
+	Iters     int       // maximum number of gradient descent steps
+	Optimizer Optimizer // update rule, GD at rate Alpha when nil
+	Theta     Matrix
+	J_history Vector
+}
+
+//
+// Constructor
+//
+func NewLinearRegression(alpha float64, iters int) *LinearRegression {
+	r := &LinearRegression{Alpha: alpha, Iters: iters}
+
+	return r
+}
+
+//
+// Fits theta to the training set, starting from zeros
+//
+func (self *LinearRegression) Fit(X Matrix, y Matrix) {
+	opt := self.Optimizer
+	if opt == nil {
+		opt = NewGD(self.Alpha)
+	}
+
+	_, n := Size(X)
+	self.Theta, self.J_history = GradientDescentWith(X, y, NewMatrix(n, 1), opt, self.Iters)
+}
+
+//
+// Predicted value for each row of X
+// p = X * theta
+//
+func (self *LinearRegression) Predict(X Matrix) (p Matrix) {
+	p, err := _mulMM(X, self.Theta)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+		return nil
+	}
+
+	return p
+}
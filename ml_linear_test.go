@@ -0,0 +1,39 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"testing"
+)
+
+func TestLinearRegression(t *testing.T) {
+	X := Matrix{{1, 1}, {1, 2}, {1, 3}, {1, 4}}
+	y := Matrix{{3}, {5}, {7}, {9}}
+
+	model := NewLinearRegression(0.05, 5000)
+	model.Fit(X, y)
+
+	p := model.Predict(Matrix{{1, 5}})
+	if Round(p[0][0], 2) != 11.0 {
+		t.Errorf("%f vs expected %f", p[0][0], 11.0)
+	}
+}
+
+func TestLinearRegressionDiverging(t *testing.T) {
+	X := Matrix{{1, 1}, {1, 2}, {1, 3}, {1, 4}}
+	y := Matrix{{3}, {5}, {7}, {9}}
+
+	// the first momentum step overshoots and raises the cost
+	model := &LinearRegression{Iters: 200, Optimizer: NewMomentum(0.3, 0.9, false)}
+	model.Fit(X, y)
+
+	// theta is the one of the last and lowest recorded cost, or the
+	// zero start when no step lowered it
+	J := ComputeLRCost(X, y, model.Theta)
+	start := ComputeLRCost(X, y, NewMatrix(2, 1))
+	if n := len(model.J_history); n > 0 && J != model.J_history[n-1] || n == 0 && J != start {
+		t.Errorf("cost %f vs expected %v from %f", J, model.J_history, start)
+	}
+}
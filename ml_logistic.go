@@ -0,0 +1,100 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	. "golog"
+)
+
+//
+// Binary logistic regression classifier trained by batch gradient
+// descent on ComputeLogisticCost, with the GD optimizer at rate Alpha
+// unless another Optimizer is set. As with ComputeLRCost, X is expected
+// to carry its own bias column of 1's and y holds 0 / 1 labels as a
+// single column matrix.
+//
+type LogisticRegression struct {
+	Alpha     float64   // learning rate
+	Lambda    float64   // L2 regularization, theta[0] is not penalized
+	Iters     int       // maximum number of gradient descent steps
+	Tolerance float64   // stop once the cost improves by less than this
+	Threshold float64   // probability at or above which Predict returns 1
+	Optimizer Optimizer // update rule, GD at rate Alpha when nil
+	Theta     Matrix
+	J_history Vector
+}
+
+//
+// Constructor
+//
+func NewLogisticRegression(alpha float64, lambda float64, iters int) *LogisticRegression {
+	r := &LogisticRegression{Alpha: alpha, Lambda: lambda, Iters: iters, Threshold: 0.5}
+
+	return r
+}
+
+//
+// Fits theta to the training set, starting from zeros
+//
+func (self *LogisticRegression) Fit(X Matrix, y Matrix) {
+	opt := self.Optimizer
+	if opt == nil {
+		opt = NewGD(self.Alpha)
+	}
+	_resetOptimizer(opt)
+
+	_, n := Size(X)
+	self.Theta = NewMatrix(n, 1)
+	self.J_history = NewEmptyVector()
+
+	var prev Matrix
+	for i := 0; i < self.Iters; i++ {
+		J, grad := ComputeLogisticCost(X, y, self.Theta, self.Lambda)
+
+		if i > 0 && self.J_history[i-1]-J < self.Tolerance {
+			// converged or diverging, a diverging step is undone
+			if J > self.J_history[i-1] {
+				self.Theta = prev
+			}
+			break
+		}
+		self.J_history = append(self.J_history, J)
+
+		prev = _copyM(self.Theta)
+		opt.Step(self.Theta, grad)
+	}
+}
+
+//
+// Probability of the positive class for each row of X
+// p = sigmoid(X * theta)
+//
+func (self *LogisticRegression) PredictProba(X Matrix) (p Matrix) {
+	z, err := _mulMM(X, self.Theta)
+	if err != nil {
+		LogErrorf("error: %s\n", err)
+		return nil
+	}
+	p = Sigmoid(z).(Matrix)
+
+	return p
+}
+
+//
+// Predicted 0 / 1 label for each row of X
+//
+func (self *LogisticRegression) Predict(X Matrix) (labels Matrix) {
+	labels = self.PredictProba(X)
+
+	for _, row := range labels {
+		if row[0] >= self.Threshold {
+			row[0] = 1
+		} else {
+			row[0] = 0
+		}
+	}
+
+	return labels
+}
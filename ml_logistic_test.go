@@ -0,0 +1,64 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"testing"
+)
+
+var testLogisticX = Matrix{{1, 0.5}, {1, 1}, {1, 1.5}, {1, 3}, {1, 3.5}, {1, 4}}
+var testLogisticY = Matrix{{0}, {0}, {0}, {1}, {1}, {1}}
+
+func TestLogisticRegressionFit(t *testing.T) {
+	model := NewLogisticRegression(1.0, 0, 1000)
+	model.Fit(testLogisticX, testLogisticY)
+
+	n := len(model.J_history)
+	if n == 0 || model.J_history[n-1] >= model.J_history[0] {
+		t.Errorf("cost did not decrease: %v", model.J_history)
+	}
+
+	labels := model.Predict(testLogisticX)
+	if !Equal(labels, testLogisticY) {
+		t.Errorf("%v != %v", labels, testLogisticY)
+	}
+}
+
+func TestLogisticRegressionDiverging(t *testing.T) {
+	// the learning rate is large enough to overshoot and raise the cost
+	model := NewLogisticRegression(50.0, 0, 1000)
+	model.Fit(testLogisticX, testLogisticY)
+
+	n := len(model.J_history)
+	if n == 0 || n == 1000 {
+		t.Fatalf("%d steps vs expected an early stop", n)
+	}
+
+	// theta is the one of the last and lowest recorded cost
+	J, _ := ComputeLogisticCost(testLogisticX, testLogisticY, model.Theta, 0)
+	if J != model.J_history[n-1] {
+		t.Errorf("cost %f vs expected %f", J, model.J_history[n-1])
+	}
+	for i := 1; i < n; i++ {
+		if model.J_history[i] > model.J_history[i-1] {
+			t.Errorf("cost increased: %v", model.J_history)
+		}
+	}
+}
+
+func TestLogisticRegressionPredictProba(t *testing.T) {
+	model := NewLogisticRegression(1.0, 1.0, 500)
+	model.Fit(testLogisticX, testLogisticY)
+
+	p := model.PredictProba(Matrix{{1, 0}, {1, 2.25}, {1, 5}})
+	if p[0][0] >= 0.5 || p[2][0] <= 0.5 {
+		t.Errorf("%v not on the expected sides of 0.5", p)
+	}
+	for i := 1; i < len(p); i++ {
+		if p[i][0] <= p[i-1][0] {
+			t.Errorf("%v is not increasing", p)
+		}
+	}
+}
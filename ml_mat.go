@@ -0,0 +1,419 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	. "golog"
+	"io"
+	"sort"
+)
+
+//
+// MATLAB Level 5 MAT-file format, as saved by MATLAB up to -v7 and
+// by Octave with -mat / -v6. Real 2-D numeric arrays of any class are
+// read as matrices, other variables (chars, cells, structs, sparse
+// and complex arrays) are skipped with a warning.
+//
+
+//
+// Class of the values written by SaveMAT
+//
+type MATClass int
+
+const (
+	MATDouble MATClass = 6
+	MATSingle MATClass = 7
+	MATInt8   MATClass = 8
+	MATUint8  MATClass = 9
+	MATInt16  MATClass = 10
+	MATUint16 MATClass = 11
+	MATInt32  MATClass = 12
+	MATUint32 MATClass = 13
+	MATInt64  MATClass = 14
+	MATUint64 MATClass = 15
+)
+
+//
+// Options for SaveMAT, the zero value writes uncompressed
+// double matrices in little-endian byte order
+//
+type MATOptions struct {
+	// MATDouble when 0, integer classes round and saturate
+	Class MATClass
+	// zlib compress each variable, as MATLAB -v7
+	Compress bool
+	// binary.LittleEndian when nil
+	ByteOrder binary.ByteOrder
+}
+
+//
+// Reported for input that isn't a Level 5 MAT-file
+//
+var ErrMATFormat = errors.New("not a Level 5 MAT-file")
+
+// data element types
+const (
+	_miINT8       = 1
+	_miUINT8      = 2
+	_miINT16      = 3
+	_miUINT16     = 4
+	_miINT32      = 5
+	_miUINT32     = 6
+	_miSINGLE     = 7
+	_miDOUBLE     = 9
+	_miINT64      = 12
+	_miUINT64     = 13
+	_miMATRIX     = 14
+	_miCOMPRESSED = 15
+)
+
+// array flags
+const (
+	_mxComplex = 0x0800
+)
+
+var _miTypes = map[uint32]_numType{
+	_miINT8:   _int8,
+	_miUINT8:  _uint8,
+	_miINT16:  _int16,
+	_miUINT16: _uint16,
+	_miINT32:  _int32,
+	_miUINT32: _uint32,
+	_miSINGLE: _float32,
+	_miDOUBLE: _float64,
+	_miINT64:  _int64,
+	_miUINT64: _uint64,
+}
+
+// data element type storing each numeric class
+var _matClassTypes = map[MATClass]uint32{
+	MATDouble: _miDOUBLE,
+	MATSingle: _miSINGLE,
+	MATInt8:   _miINT8,
+	MATUint8:  _miUINT8,
+	MATInt16:  _miINT16,
+	MATUint16: _miUINT16,
+	MATInt32:  _miINT32,
+	MATUint32: _miUINT32,
+	MATInt64:  _miINT64,
+	MATUint64: _miUINT64,
+}
+
+//
+// Padding to the next 8 byte boundary
+//
+func _matPadding(n int) int {
+	return (8 - n%8) % 8
+}
+
+//
+// Reads a top level data element, io.EOF at the end of the file
+//
+func _matReadElement(r io.Reader, order binary.ByteOrder) (typ uint32, data []byte, err error) {
+	tag := make([]byte, 8)
+	if _, err = io.ReadFull(r, tag); err != nil {
+		return 0, nil, err
+	}
+
+	typ, data, _, err = _matElement(tag, order)
+	if err != io.ErrShortBuffer {
+		return typ, data, err
+	}
+
+	n := int(order.Uint32(tag[4:]))
+	if typ != _miCOMPRESSED {
+		n += _matPadding(n)
+	}
+	data = make([]byte, n)
+	if _, err = io.ReadFull(r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+
+	return typ, data[:order.Uint32(tag[4:])], nil
+}
+
+//
+// Splits the data element at the start of buf, handling the small
+// element format that packs up to 4 bytes into the tag. Returns
+// io.ErrShortBuffer when buf holds only the tag of a larger element.
+//
+func _matElement(buf []byte, order binary.ByteOrder) (typ uint32, data []byte, rest []byte, err error) {
+	if len(buf) < 8 {
+		return 0, nil, nil, io.ErrUnexpectedEOF
+	}
+
+	word := order.Uint32(buf)
+	if small := word >> 16; small != 0 {
+		if small > 4 {
+			return 0, nil, nil, ErrMATFormat
+		}
+		return word & 0xffff, buf[4 : 4+small], buf[8:], nil
+	}
+
+	typ = word
+	n := int(order.Uint32(buf[4:]))
+	if n == 0 {
+		return typ, []byte{}, buf[8:], nil
+	}
+	if len(buf) < 8+n {
+		return typ, nil, nil, io.ErrShortBuffer
+	}
+
+	end := 8 + n + _matPadding(n)
+	if end > len(buf) {
+		end = len(buf)
+	}
+
+	return typ, buf[8 : 8+n], buf[end:], nil
+}
+
+//
+// Wraps a malformed array subelement error
+//
+func _matFormatError(name string, part string, err error) error {
+	if err == nil {
+		err = ErrMATFormat
+	}
+
+	return fmt.Errorf("mat: %s: %s: %w", name, part, err)
+}
+
+//
+// Decodes a miMATRIX element, returning a nil matrix for
+// the unsupported arrays
+//
+func _matArray(data []byte, order binary.ByteOrder) (name string, matrix Matrix, err error) {
+	var typ uint32
+	var flags, dims, label, real []byte
+
+	_, flags, data, err = _matElement(data, order)
+	if err != nil || len(flags) < 8 {
+		return "", nil, _matFormatError(name, "array flags", err)
+	}
+	typ, dims, data, err = _matElement(data, order)
+	if err != nil || typ != _miINT32 {
+		return "", nil, _matFormatError(name, "dimensions", err)
+	}
+	typ, label, data, err = _matElement(data, order)
+	if err != nil || typ != _miINT8 {
+		return "", nil, _matFormatError(name, "array name", err)
+	}
+	name = string(label)
+
+	class := MATClass(order.Uint32(flags) & 0xff)
+	if _, ok := _matClassTypes[class]; !ok {
+		LogWarnf("mat: %s: skipping unsupported class %d\n", name, class)
+		return name, nil, nil
+	}
+	if order.Uint32(flags)&_mxComplex != 0 {
+		LogWarnf("mat: %s: skipping complex array\n", name)
+		return name, nil, nil
+	}
+	shape := _decodeNumbers(dims, _int32, order)
+	if len(shape) != 2 {
+		LogWarnf("mat: %s: skipping %d-d array\n", name, len(shape))
+		return name, nil, nil
+	}
+
+	// the real part may be stored in a smaller type than the class
+	typ, real, _, err = _matElement(data, order)
+	numType, ok := _miTypes[typ]
+	if err != nil || !ok {
+		return "", nil, _matFormatError(name, "real part", err)
+	}
+
+	// check the dimensions against the element length before
+	// allocating, as int64 the product of the int32 ones can't overflow
+	rows, cols := int(shape[0]), int(shape[1])
+	size := numType.size()
+	if rows < 0 || cols < 0 || len(real)%size != 0 || int64(rows)*int64(cols) != int64(len(real)/size) {
+		return "", nil, _matFormatError(name, fmt.Sprintf("%d bytes for a %dx%d array", len(real), rows, cols), nil)
+	}
+	values := _decodeNumbers(real, numType, order)
+
+	// column major
+	matrix = NewMatrix(rows, cols)
+	for col := 0; col < cols; col++ {
+		for row := 0; row < rows; row++ {
+			matrix[row][col] = values[col*rows+row]
+		}
+	}
+
+	return name, matrix, nil
+}
+
+//
+// Loads the numeric variables of a Level 5 MAT-file
+//
+func LoadMAT(r io.Reader) (vars map[string]Matrix, err error) {
+	header := make([]byte, 128)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("mat: header: %w", ErrMATFormat)
+	}
+
+	var order binary.ByteOrder
+	switch string(header[126:]) {
+	case "IM":
+		order = binary.LittleEndian
+	case "MI":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("mat: endian indicator: %w", ErrMATFormat)
+	}
+	if order.Uint16(header[124:]) != 0x0100 {
+		return nil, fmt.Errorf("mat: version %#x: %w", order.Uint16(header[124:]), ErrMATFormat)
+	}
+
+	vars = make(map[string]Matrix)
+	for {
+		typ, data, err := _matReadElement(r, order)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("mat: %w", err)
+		}
+
+		if typ == _miCOMPRESSED {
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("mat: %w", err)
+			}
+			typ, data, err = _matReadElement(zr, order)
+			if err != nil {
+				return nil, fmt.Errorf("mat: compressed element: %w", err)
+			}
+		}
+		if typ != _miMATRIX {
+			continue
+		}
+
+		name, matrix, err := _matArray(data, order)
+		if err != nil {
+			return nil, err
+		}
+		if matrix != nil {
+			vars[name] = matrix
+		}
+	}
+
+	return vars, nil
+}
+
+//
+// Appends a data element with its tag and padding,
+// using the small element format for up to 4 bytes
+//
+func _matPutElement(buf *bytes.Buffer, typ uint32, data []byte, order binary.ByteOrder) {
+	tag := make([]byte, 8)
+	if n := len(data); n > 0 && n <= 4 {
+		order.PutUint32(tag, uint32(n)<<16|typ)
+		copy(tag[4:], data)
+		buf.Write(tag)
+		return
+	}
+
+	order.PutUint32(tag, typ)
+	order.PutUint32(tag[4:], uint32(len(data)))
+	buf.Write(tag)
+	buf.Write(data)
+	buf.Write(make([]byte, _matPadding(len(data))))
+}
+
+//
+// Saves the named matrices as a Level 5 MAT-file, loadable
+// with load in MATLAB and Octave, in name order
+//
+func SaveMAT(w io.Writer, vars map[string]Matrix, opts *MATOptions) (err error) {
+	if opts == nil {
+		opts = &MATOptions{}
+	}
+	class := opts.Class
+	if class == 0 {
+		class = MATDouble
+	}
+	miType, ok := _matClassTypes[class]
+	if !ok {
+		return fmt.Errorf("mat: unsupported class %d", class)
+	}
+	order := opts.ByteOrder
+	if order == nil {
+		order = binary.LittleEndian
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		if !_varName.MatchString(name) {
+			return fmt.Errorf("mat: invalid variable name %q", name)
+		}
+		if err = _checkRows("mat", vars[name]); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	header := bytes.Repeat([]byte{' '}, 128)
+	copy(header, "MATLAB 5.0 MAT-file, Created by: goml")
+	copy(header[116:124], make([]byte, 8))
+	order.PutUint16(header[124:], 0x0100)
+	order.PutUint16(header[126:], 'M'<<8|'I')
+	if _, err = w.Write(header); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		matrix := vars[name]
+		rows, cols := Size(matrix)
+
+		// column major
+		values := make([]float64, 0, rows*cols)
+		for col := 0; col < cols; col++ {
+			for row := 0; row < rows; row++ {
+				values = append(values, matrix[row][col])
+			}
+		}
+
+		var array bytes.Buffer
+		flags := make([]byte, 8)
+		order.PutUint32(flags, uint32(class))
+		_matPutElement(&array, _miUINT32, flags, order)
+		_matPutElement(&array, _miINT32, _encodeNumbers([]float64{float64(rows), float64(cols)}, _int32, order), order)
+		_matPutElement(&array, _miINT8, []byte(name), order)
+		_matPutElement(&array, miType, _encodeNumbers(values, _miTypes[miType], order), order)
+
+		var element bytes.Buffer
+		_matPutElement(&element, _miMATRIX, array.Bytes(), order)
+
+		if opts.Compress {
+			var compressed bytes.Buffer
+			zw := zlib.NewWriter(&compressed)
+			zw.Write(element.Bytes())
+			zw.Close()
+
+			// compressed elements aren't padded
+			element.Reset()
+			tag := make([]byte, 8)
+			order.PutUint32(tag, _miCOMPRESSED)
+			order.PutUint32(tag[4:], uint32(compressed.Len()))
+			element.Write(tag)
+			element.Write(compressed.Bytes())
+		}
+
+		if _, err = w.Write(element.Bytes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
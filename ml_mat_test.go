@@ -0,0 +1,176 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var testMATVars = map[string]Matrix{
+	"X":     {{1, 2, 3}, {4, 5, 6}},
+	"y":     {{-1}, {0}, {1}, {2}},
+	"theta": {{0.5, -0.25}},
+}
+
+func TestSaveLoadMAT(t *testing.T) {
+	for _, opts := range []*MATOptions{
+		nil,
+		{Compress: true},
+		{ByteOrder: binary.BigEndian},
+		{Class: MATSingle, Compress: true},
+		{Class: MATInt16, ByteOrder: binary.BigEndian},
+		{Class: MATInt64},
+		{Class: MATUint8},
+	} {
+		// integer classes round theta, unsigned ones clip y
+		vars := testMATVars
+		if opts != nil && opts.Class >= MATInt8 {
+			vars = map[string]Matrix{"X": testMATVars["X"]}
+		}
+
+		var buf bytes.Buffer
+		if err := SaveMAT(&buf, vars, opts); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadMAT(&buf)
+		if err != nil {
+			t.Fatalf("%+v: %v", opts, err)
+		}
+
+		if len(loaded) != len(vars) {
+			t.Errorf("%+v: %d variables vs expected %d", opts, len(loaded), len(vars))
+		}
+		for name, matrix := range vars {
+			if !Equal(loaded[name], matrix) {
+				t.Errorf("%+v: %s: %v != %v", opts, name, loaded[name], matrix)
+			}
+		}
+	}
+}
+
+func TestSaveMATSaturate(t *testing.T) {
+	var buf bytes.Buffer
+	SaveMAT(&buf, map[string]Matrix{"a": {{300, -3.6, 1.4}}}, &MATOptions{Class: MATInt8})
+
+	loaded, err := LoadMAT(&buf)
+	exp := Matrix{{127, -4, 1}}
+	if err != nil || !Equal(loaded["a"], exp) {
+		t.Errorf("%v != %v (%v)", loaded["a"], exp, err)
+	}
+}
+
+func TestLoadMATFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "data.mat")
+	file, err := os.Create(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SaveMAT(file, testMATVars, &MATOptions{Compress: true})
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err = os.Open(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	loaded, err := LoadMAT(file)
+	if err != nil || !Equal(loaded["X"], testMATVars["X"]) {
+		t.Errorf("%v != %v (%v)", loaded["X"], testMATVars["X"], err)
+	}
+}
+
+func TestLoadMATCompact(t *testing.T) {
+	order := binary.LittleEndian
+
+	// double array stored as uint8, as MATLAB does for small integers
+	var array bytes.Buffer
+	flags := make([]byte, 8)
+	order.PutUint32(flags, uint32(MATDouble))
+	_matPutElement(&array, _miUINT32, flags, order)
+	_matPutElement(&array, _miINT32, _encodeNumbers([]float64{2, 2}, _int32, order), order)
+	_matPutElement(&array, _miINT8, []byte("ab"), order)
+	_matPutElement(&array, _miUINT8, []byte{1, 2, 3, 4}, order)
+
+	// char array, which is skipped
+	var chars bytes.Buffer
+	order.PutUint32(flags, 4)
+	_matPutElement(&chars, _miUINT32, flags, order)
+	_matPutElement(&chars, _miINT32, _encodeNumbers([]float64{1, 2}, _int32, order), order)
+	_matPutElement(&chars, _miINT8, []byte("s"), order)
+	_matPutElement(&chars, _miUINT16, _encodeNumbers([]float64{'h', 'i'}, _uint16, order), order)
+
+	var buf bytes.Buffer
+	SaveMAT(&buf, nil, nil)
+	_matPutElement(&buf, _miMATRIX, array.Bytes(), order)
+	_matPutElement(&buf, _miMATRIX, chars.Bytes(), order)
+
+	loaded, err := LoadMAT(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := Matrix{{1, 3}, {2, 4}}
+	if !Equal(loaded["ab"], exp) {
+		t.Errorf("%v != %v", loaded["ab"], exp)
+	}
+	if _, ok := loaded["s"]; ok || len(loaded) != 1 {
+		t.Errorf("%v vs expected only ab", loaded)
+	}
+}
+
+func TestLoadMATErrors(t *testing.T) {
+	_, err := LoadMAT(bytes.NewReader(bytes.Repeat([]byte{' '}, 128)))
+	if !errors.Is(err, ErrMATFormat) {
+		t.Errorf("%v is not %v", err, ErrMATFormat)
+	}
+
+	var buf bytes.Buffer
+	SaveMAT(&buf, testMATVars, nil)
+	_, err = LoadMAT(bytes.NewReader(buf.Bytes()[:buf.Len()-20]))
+	if err == nil {
+		t.Errorf("truncated file vs expected error")
+	}
+
+	if err := SaveMAT(&buf, map[string]Matrix{"_x": {{1}}}, nil); err == nil {
+		t.Errorf("invalid name vs expected error")
+	}
+
+	var dimErr *DimensionError
+	if err := SaveMAT(&buf, map[string]Matrix{"x": {{1, 2}, {3}}}, nil); !errors.As(err, &dimErr) {
+		t.Errorf("%v vs expected a dimension error for a ragged matrix", err)
+	}
+}
+
+func TestLoadMATBadDims(t *testing.T) {
+	order := binary.LittleEndian
+	flags := make([]byte, 8)
+	order.PutUint32(flags, uint32(MATDouble))
+
+	// negative dimensions whose product matches the 4 values, too few
+	// values, and a product overflowing 32 bits
+	for _, dims := range [][]float64{{-1, -4}, {-2, -2}, {2, 3}, {65536, 65536}} {
+		var array bytes.Buffer
+		_matPutElement(&array, _miUINT32, flags, order)
+		_matPutElement(&array, _miINT32, _encodeNumbers(dims, _int32, order), order)
+		_matPutElement(&array, _miINT8, []byte("x"), order)
+		_matPutElement(&array, _miUINT8, []byte{1, 2, 3, 4}, order)
+
+		var buf bytes.Buffer
+		SaveMAT(&buf, nil, nil)
+		_matPutElement(&buf, _miMATRIX, array.Bytes(), order)
+
+		if _, err := LoadMAT(&buf); !errors.Is(err, ErrMATFormat) {
+			t.Errorf("%v: %v is not %v", dims, err, ErrMATFormat)
+		}
+	}
+}
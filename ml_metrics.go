@@ -0,0 +1,572 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"fmt"
+	. "golog"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// Confusion matrix for any number of classes, indexed [actual][predicted]
+// in the order of labels. When labels is nil the sorted union of the
+// actual and predicted values is used. Values that are not in labels
+// are ignored.
+//
+func ConfusionMatrix(actual Vector, predicted Vector, labels Vector) (cm [][]int, classes Vector) {
+	if len(actual) != len(predicted) {
+		LogErrorf("error: confusion: %d actual vs %d predicted values\n", len(actual), len(predicted))
+		return nil, nil
+	}
+
+	classes = labels
+	if classes == nil {
+		seen := make(map[float64]bool)
+		for _, v := range append(append(Vector{}, actual...), predicted...) {
+			if !seen[v] {
+				seen[v] = true
+				classes = append(classes, v)
+			}
+		}
+		sort.Float64s(classes)
+	}
+
+	index := make(map[float64]int)
+	for i, label := range classes {
+		index[label] = i
+	}
+
+	cm = make([][]int, len(classes))
+	for i := range cm {
+		cm[i] = make([]int, len(classes))
+	}
+	for i, a := range actual {
+		ai, aok := index[a]
+		pi, pok := index[predicted[i]]
+		if aok && pok {
+			cm[ai][pi] += 1
+		}
+	}
+
+	return cm, classes
+}
+
+//
+// Precision, recall, F1 score and support (number of actual occurrences)
+// for a class, or averaged over the classes
+//
+type ClassMetrics struct {
+	Precision float64
+	Recall    float64
+	F1        float64
+	Support   int
+}
+
+//
+// Per-class metrics with their micro, macro and support weighted
+// averages. Micro averages pool the counts of all classes, macro
+// averages weigh each class equally.
+//
+type ClassificationReport struct {
+	Labels   Vector
+	Classes  []ClassMetrics
+	Accuracy float64
+	Micro    ClassMetrics
+	Macro    ClassMetrics
+	Weighted ClassMetrics
+}
+
+//
+// Ratio guarded against an empty denominator, which
+// scores 0 as with an undefined precision or recall
+//
+func _ratio(num float64, den float64) float64 {
+	if den == 0 {
+		return 0
+	}
+
+	return num / den
+}
+
+//
+// Harmonic mean of precision and recall
+//
+func _f1(precision float64, recall float64) float64 {
+	return _ratio(2*precision*recall, precision+recall)
+}
+
+//
+// Creates the classification report for the actual and predicted
+// labels, see ConfusionMatrix for the handling of labels
+//
+func NewClassificationReport(actual Vector, predicted Vector, labels Vector) (report *ClassificationReport) {
+	cm, classes := ConfusionMatrix(actual, predicted, labels)
+	if cm == nil {
+		return nil
+	}
+
+	report = &ClassificationReport{Labels: classes, Classes: make([]ClassMetrics, len(classes))}
+
+	n := len(classes)
+	tpSum, fpSum, fnSum, total := 0, 0, 0, 0
+	for i := 0; i < n; i++ {
+		tp, fp, fn := cm[i][i], 0, 0
+		for j := 0; j < n; j++ {
+			if j != i {
+				fn += cm[i][j]
+				fp += cm[j][i]
+			}
+		}
+
+		class := &report.Classes[i]
+		class.Precision = _ratio(float64(tp), float64(tp+fp))
+		class.Recall = _ratio(float64(tp), float64(tp+fn))
+		class.F1 = _f1(class.Precision, class.Recall)
+		class.Support = tp + fn
+
+		tpSum += tp
+		fpSum += fp
+		fnSum += fn
+		total += class.Support
+
+		report.Macro.Precision += class.Precision / float64(n)
+		report.Macro.Recall += class.Recall / float64(n)
+		report.Macro.F1 += class.F1 / float64(n)
+
+		weight := float64(class.Support)
+		report.Weighted.Precision += class.Precision * weight
+		report.Weighted.Recall += class.Recall * weight
+		report.Weighted.F1 += class.F1 * weight
+	}
+
+	report.Accuracy = _ratio(float64(tpSum), float64(total))
+
+	report.Micro.Precision = _ratio(float64(tpSum), float64(tpSum+fpSum))
+	report.Micro.Recall = _ratio(float64(tpSum), float64(tpSum+fnSum))
+	report.Micro.F1 = _f1(report.Micro.Precision, report.Micro.Recall)
+
+	report.Weighted.Precision = _ratio(report.Weighted.Precision, float64(total))
+	report.Weighted.Recall = _ratio(report.Weighted.Recall, float64(total))
+	report.Weighted.F1 = _ratio(report.Weighted.F1, float64(total))
+
+	report.Micro.Support = total
+	report.Macro.Support = total
+	report.Weighted.Support = total
+
+	return report
+}
+
+//
+// Formats the report as an aligned table
+//
+func (self *ClassificationReport) String() string {
+	names := make([]string, len(self.Labels))
+	width := len("weighted avg")
+	for i, label := range self.Labels {
+		names[i] = strconv.FormatFloat(label, 'g', -1, 64)
+		if len(names[i]) > width {
+			width = len(names[i])
+		}
+	}
+
+	var b strings.Builder
+	row := func(name string, m ClassMetrics) {
+		fmt.Fprintf(&b, "%*s %10.4f %10.4f %10.4f %10d\n", width, name, m.Precision, m.Recall, m.F1, m.Support)
+	}
+
+	fmt.Fprintf(&b, "%*s %10s %10s %10s %10s\n\n", width, "", "precision", "recall", "f1-score", "support")
+	for i, class := range self.Classes {
+		row(names[i], class)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "%*s %10s %10s %10.4f %10d\n", width, "accuracy", "", "", self.Accuracy, self.Micro.Support)
+	row("micro avg", self.Micro)
+	row("macro avg", self.Macro)
+	row("weighted avg", self.Weighted)
+
+	return b.String()
+}
+
+//
+// Regression metrics comparing actual and predicted values given as
+// vectors or column matrices. Mismatched arguments are logged and
+// score NaN.
+//
+
+//
+// Vector of a regression metric argument, or nil when it
+// is neither a vector nor a column matrix
+//
+func _regressionVector(arg Data) (v Vector) {
+	arg, _ = _undense(arg)
+
+	switch arg.(type) {
+	case Vector:
+		v = arg.(Vector)
+	case Matrix:
+		if _, cols := Size(arg); cols == 1 {
+			v = Unroll(arg.(Matrix))
+		}
+	}
+
+	return v
+}
+
+//
+// Flattens the actual and predicted values of a regression metric
+//
+func _regressionVectors(op string, actual Data, predicted Data) (a Vector, p Vector, ok bool) {
+	a = _regressionVector(actual)
+	p = _regressionVector(predicted)
+
+	if a == nil || p == nil {
+		LogErrorf("error: %v\n", &TypeError{Op: op, Type1: reflect.TypeOf(actual), Type2: reflect.TypeOf(predicted)})
+		return nil, nil, false
+	}
+	if len(a) != len(p) || len(a) == 0 {
+		LogErrorf("error: %v\n", _dimensionError(op, actual, predicted))
+		return nil, nil, false
+	}
+
+	return a, p, true
+}
+
+//
+// Residuals actual - predicted
+//
+func _residuals(a Vector, p Vector) (r Vector) {
+	r = NewVector(len(a))
+	for i := range a {
+		r[i] = a[i] - p[i]
+	}
+
+	return r
+}
+
+//
+// Mean squared error
+// mse = mean((y - p) .^ 2)
+//
+func MSE(actual Data, predicted Data) float64 {
+	a, p, ok := _regressionVectors("mse", actual, predicted)
+	if !ok {
+		return math.NaN()
+	}
+
+	r := _residuals(a, p)
+
+	for i, val := range r {
+		r[i] = val * val
+	}
+
+	return Mean((*[]float64)(&r))
+}
+
+//
+// Root mean squared error
+// rmse = sqrt(mse)
+//
+func RMSE(actual Data, predicted Data) float64 {
+	return math.Sqrt(MSE(actual, predicted))
+}
+
+//
+// Mean absolute error
+// mae = mean(abs(y - p))
+//
+func MAE(actual Data, predicted Data) float64 {
+	a, p, ok := _regressionVectors("mae", actual, predicted)
+	if !ok {
+		return math.NaN()
+	}
+
+	r := _residuals(a, p)
+
+	for i, val := range r {
+		r[i] = math.Abs(val)
+	}
+
+	return Mean((*[]float64)(&r))
+}
+
+//
+// Median absolute error, robust to outliers
+// medae = median(abs(y - p))
+//
+func MedianAE(actual Data, predicted Data) float64 {
+	a, p, ok := _regressionVectors("medae", actual, predicted)
+	if !ok {
+		return math.NaN()
+	}
+
+	r := _residuals(a, p)
+
+	for i, val := range r {
+		r[i] = math.Abs(val)
+	}
+	sort.Float64s(r)
+
+	return Median((*[]float64)(&r))
+}
+
+//
+// Mean absolute percentage error, in percent. Zero actual values
+// make the error infinite.
+// mape = 100 * mean(abs((y - p) ./ y))
+//
+func MAPE(actual Data, predicted Data) float64 {
+	a, p, ok := _regressionVectors("mape", actual, predicted)
+	if !ok {
+		return math.NaN()
+	}
+
+	r := NewVector(len(a))
+	for i := range a {
+		r[i] = math.Abs((a[i] - p[i]) / a[i])
+	}
+
+	return 100 * Mean((*[]float64)(&r))
+}
+
+//
+// Coefficient of determination, 1 for a perfect fit and 0 for
+// always predicting the mean. A constant actual scores 1 when
+// predicted exactly and 0 otherwise.
+// r2 = 1 - sum((y - p) .^ 2) / sum((y - mean(y)) .^ 2)
+//
+func R2(actual Data, predicted Data) float64 {
+	a, p, ok := _regressionVectors("r2", actual, predicted)
+	if !ok {
+		return math.NaN()
+	}
+
+	mean := Mean((*[]float64)(&a))
+	ssRes, ssTot := 0.0, 0.0
+	for i := range a {
+		ssRes += (a[i] - p[i]) * (a[i] - p[i])
+		ssTot += (a[i] - mean) * (a[i] - mean)
+	}
+
+	if ssTot == 0 {
+		if ssRes == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	return 1 - ssRes/ssTot
+}
+
+//
+// R2 adjusted for the number of features p of the model
+// adjr2 = 1 - (1 - r2) * (n - 1) / (n - p - 1)
+//
+func AdjustedR2(actual Data, predicted Data, p int) float64 {
+	a := _regressionVector(actual)
+	n := len(a)
+	if n-p-1 <= 0 {
+		LogErrorf("error: adjusted r2: %d samples for %d features\n", n, p)
+		return math.NaN()
+	}
+
+	r2 := R2(actual, predicted)
+
+	return 1 - (1-r2)*float64(n-1)/float64(n-p-1)
+}
+
+//
+// Explained variance, which unlike R2 ignores a constant bias
+// in the predictions
+// ev = 1 - var(y - p) / var(y)
+//
+func ExplainedVariance(actual Data, predicted Data) float64 {
+	a, p, ok := _regressionVectors("explained variance", actual, predicted)
+	if !ok {
+		return math.NaN()
+	}
+
+	r := _residuals(a, p)
+
+	varY := math.Pow(StandardDeviation((*[]float64)(&a)), 2)
+	varR := math.Pow(StandardDeviation((*[]float64)(&r)), 2)
+
+	if varY == 0 {
+		if varR == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	return 1 - varR/varY
+}
+
+//
+// Threshold metrics score binary classifier outputs, labels being 1
+// for the positive class and 0 otherwise. A sample is predicted
+// positive when its score is >= the threshold.
+//
+
+//
+// Metric of a 2x2 confusion matrix maximized by BestThreshold,
+// eg. F1Score or YoudenJ
+//
+type ThresholdMetric func(ap [][]int) float64
+
+//
+// Cumulative true and false positive counts at each distinct score,
+// in descending order of the thresholds
+//
+func _thresholdCounts(scores Vector, labels Vector) (thresholds Vector, tps []int, fps []int, positives int, negatives int) {
+	if len(scores) != len(labels) {
+		LogErrorf("error: %v\n", _dimensionError("threshold", scores, labels))
+		return nil, nil, nil, 0, 0
+	}
+
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i int, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	tp, fp := 0, 0
+	for i, idx := range order {
+		if labels[idx] == 1 {
+			tp++
+		} else {
+			fp++
+		}
+
+		// emit once all samples tied at this score are counted
+		if i == len(order)-1 || scores[order[i+1]] != scores[idx] {
+			thresholds = append(thresholds, scores[idx])
+			tps = append(tps, tp)
+			fps = append(fps, fp)
+		}
+	}
+
+	return thresholds, tps, fps, tp, fp
+}
+
+//
+// Checks that the labels hold both classes, as the rates of a
+// missing one are 0 / 0
+//
+func _hasClasses(op string, positives int, negatives int) bool {
+	if positives == 0 || negatives == 0 {
+		LogErrorf("error: %s: %d positive and %d negative labels, expected both classes\n", op, positives, negatives)
+		return false
+	}
+
+	return true
+}
+
+//
+// Receiver operating characteristic curve, the false and true positive
+// rates for descending thresholds. The curve starts at (0, 0) with
+// a threshold of +Inf. The labels must hold both classes.
+//
+func ROCCurve(scores Vector, labels Vector) (fpr Vector, tpr Vector, thresholds Vector) {
+	cuts, tps, fps, positives, negatives := _thresholdCounts(scores, labels)
+	if cuts == nil || !_hasClasses("roc curve", positives, negatives) {
+		return nil, nil, nil
+	}
+
+	fpr = Vector{0}
+	tpr = Vector{0}
+	thresholds = Vector{math.Inf(1)}
+	for i, cut := range cuts {
+		fpr = append(fpr, _ratio(float64(fps[i]), float64(negatives)))
+		tpr = append(tpr, _ratio(float64(tps[i]), float64(positives)))
+		thresholds = append(thresholds, cut)
+	}
+
+	return fpr, tpr, thresholds
+}
+
+//
+// Precision-recall curve for descending thresholds, so recall
+// increases along the curve. The labels must hold positives.
+//
+func PRCurve(scores Vector, labels Vector) (precision Vector, recall Vector, thresholds Vector) {
+	thresholds, tps, fps, positives, _ := _thresholdCounts(scores, labels)
+	if thresholds == nil {
+		return nil, nil, nil
+	}
+	if positives == 0 {
+		LogErrorf("error: pr curve: no positive labels\n")
+		return nil, nil, nil
+	}
+
+	precision = NewVector(len(thresholds))
+	recall = NewVector(len(thresholds))
+	for i := range thresholds {
+		precision[i] = _ratio(float64(tps[i]), float64(tps[i]+fps[i]))
+		recall[i] = _ratio(float64(tps[i]), float64(positives))
+	}
+
+	return precision, recall, thresholds
+}
+
+//
+// Area under the curve y(x) by the trapezoidal rule, eg. AUC(ROCCurve(...))
+// for the ROC AUC. The x values must be monotonic.
+//
+func AUC(x Vector, y Vector) (area float64) {
+	for i := 1; i < len(x); i++ {
+		area += (x[i] - x[i-1]) * (y[i] + y[i-1]) / 2
+	}
+
+	return math.Abs(area)
+}
+
+//
+// Average precision, the precision at each threshold weighted by the
+// increase in recall. Unlike the trapezoidal AUC of the PR curve it
+// doesn't interpolate between the points, NaN without positives.
+// ap = sum((R(n) - R(n - 1)) * P(n))
+//
+func AveragePrecision(scores Vector, labels Vector) (ap float64) {
+	precision, recall, _ := PRCurve(scores, labels)
+	if precision == nil {
+		return math.NaN()
+	}
+
+	prev := 0.0
+	for i := range precision {
+		ap += (recall[i] - prev) * precision[i]
+		prev = recall[i]
+	}
+
+	return ap
+}
+
+//
+// Searches the distinct scores for the threshold maximizing metric,
+// preferring the highest threshold on ties. Both are NaN unless the
+// labels hold both classes.
+//
+func BestThreshold(scores Vector, labels Vector, metric ThresholdMetric) (threshold float64, score float64) {
+	thresholds, tps, fps, positives, negatives := _thresholdCounts(scores, labels)
+	if thresholds == nil || !_hasClasses("best threshold", positives, negatives) {
+		return math.NaN(), math.NaN()
+	}
+
+	threshold, score = math.NaN(), math.Inf(-1)
+	for i, cut := range thresholds {
+		ap := [][]int{{tps[i], positives - tps[i]}, {fps[i], negatives - fps[i]}}
+		if val := metric(ap); val > score {
+			threshold, score = cut, val
+		}
+	}
+
+	return threshold, score
+}
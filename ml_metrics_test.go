@@ -0,0 +1,177 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+var testActual = Vector{0, 0, 0, 1, 1, 1, 1, 2, 2, 2}
+var testPredicted = Vector{0, 0, 1, 1, 1, 1, 2, 2, 2, 0}
+
+func TestConfusionMatrix(t *testing.T) {
+	cm, labels := ConfusionMatrix(testActual, testPredicted, nil)
+	if !Equal(labels, Vector{0, 1, 2}) {
+		t.Errorf("%v != %v", labels, Vector{0, 1, 2})
+	}
+
+	exp := [][]int{{2, 1, 0}, {0, 3, 1}, {1, 0, 2}}
+	for i := range exp {
+		for j := range exp[i] {
+			if cm[i][j] != exp[i][j] {
+				t.Errorf("%v != %v", cm, exp)
+				return
+			}
+		}
+	}
+
+	// explicit labels select and order the classes
+	cm, _ = ConfusionMatrix(testActual, testPredicted, Vector{2, 1})
+	if cm[0][0] != 2 || cm[0][1] != 0 || cm[1][0] != 1 || cm[1][1] != 3 {
+		t.Errorf("%v != %v", cm, [][]int{{2, 0}, {1, 3}})
+	}
+}
+
+func TestClassificationReport(t *testing.T) {
+	report := NewClassificationReport(testActual, testPredicted, nil)
+
+	_testA(t, report.Accuracy, 0.7)
+
+	class := report.Classes[1]
+	_testAB(t, class.Precision, 0.75, class.Recall, 0.75)
+	if class.Support != 4 {
+		t.Errorf("support %d vs expected %d", class.Support, 4)
+	}
+
+	// single-label micro averages reduce to the accuracy
+	_testAB(t, report.Micro.Precision, 0.7, report.Micro.Recall, 0.7)
+
+	// (2/3 + 3/4 + 2/3) / 3
+	_testA(t, report.Macro.Precision, 0.694444)
+	// (3 * 2/3 + 4 * 3/4 + 3 * 2/3) / 10
+	_testA(t, report.Weighted.Precision, 0.7)
+}
+
+func TestClassificationReportString(t *testing.T) {
+	report := NewClassificationReport(testActual, testPredicted, nil)
+	lines := strings.Split(strings.TrimRight(report.String(), "\n"), "\n")
+
+	// header, 3 classes, accuracy and 3 averages with 2 blank lines
+	if len(lines) != 10 {
+		t.Fatalf("%d lines vs expected %d:\n%s", len(lines), 10, report)
+	}
+	for _, line := range lines {
+		if line != "" && len(line) != len(lines[0]) {
+			t.Errorf("misaligned table:\n%s", report)
+			break
+		}
+	}
+}
+
+var testRegActual = Vector{3, -0.5, 2, 7}
+var testRegPredicted = Vector{2.5, 0, 2, 8}
+
+func TestRegressionMetrics(t *testing.T) {
+	_testAB(t, MSE(testRegActual, testRegPredicted), 0.375, RMSE(testRegActual, testRegPredicted), 0.612372)
+	_testAB(t, MAE(testRegActual, testRegPredicted), 0.5, MedianAE(testRegActual, testRegPredicted), 0.5)
+	_testA(t, MAPE(testRegActual, testRegPredicted), 32.738095)
+	_testAB(t, R2(testRegActual, testRegPredicted), 0.948608, AdjustedR2(testRegActual, testRegPredicted, 1), 0.922912)
+	_testA(t, ExplainedVariance(testRegActual, testRegPredicted), 0.957173)
+}
+
+func TestRegressionMetricsColumns(t *testing.T) {
+	actual := Reshape(testRegActual, 4, 1)
+	predicted := Reshape(testRegPredicted, 4, 1)
+
+	_testA(t, MSE(actual, predicted), 0.375)
+	_testA(t, R2(actual, testRegPredicted), 0.948608)
+
+	// the mean squared error agrees with the cost of the same residuals
+	theta := Matrix{{0}, {1}}
+	X := Join(Ones(4, 1), predicted).(Matrix)
+	_testA(t, MSE(actual, predicted)/2, Round(ComputeLRCost(X, actual, theta), 6))
+
+	if !math.IsNaN(MSE(actual, Vector{1, 2})) {
+		t.Errorf("mismatched lengths vs expected NaN")
+	}
+	if !math.IsNaN(MSE(Matrix{{1, 2}, {3, 4}}, Matrix{{1, 2}, {3, 4}})) {
+		t.Errorf("multi-column matrix vs expected NaN")
+	}
+}
+
+func TestR2Bias(t *testing.T) {
+	// a constant offset costs R2 but not the explained variance
+	predicted := Vector{4, 0.5, 3, 8}
+	_testA(t, ExplainedVariance(testRegActual, predicted), 1)
+	if R2(testRegActual, predicted) >= 1 {
+		t.Errorf("biased predictions vs expected R2 < 1")
+	}
+}
+
+var testScores = Vector{0.1, 0.4, 0.35, 0.8}
+var testLabels = Vector{0, 0, 1, 1}
+
+func TestROCCurve(t *testing.T) {
+	fpr, tpr, thresholds := ROCCurve(testScores, testLabels)
+
+	if !Equal(fpr, Vector{0, 0, 0.5, 0.5, 1}) || !Equal(tpr, Vector{0, 0.5, 0.5, 1, 1}) {
+		t.Errorf("fpr %v tpr %v", fpr, tpr)
+	}
+	if !math.IsInf(thresholds[0], 1) || !Equal(thresholds[1:], Vector{0.8, 0.4, 0.35, 0.1}) {
+		t.Errorf("thresholds %v", thresholds)
+	}
+
+	_testA(t, AUC(fpr, tpr), 0.75)
+}
+
+func TestROCCurveTies(t *testing.T) {
+	// tied scores form a single diagonal step
+	fpr, tpr, _ := ROCCurve(Vector{0.5, 0.5, 0.5, 0.5}, testLabels)
+	if !Equal(fpr, Vector{0, 1}) || !Equal(tpr, Vector{0, 1}) {
+		t.Errorf("fpr %v tpr %v", fpr, tpr)
+	}
+	_testA(t, AUC(fpr, tpr), 0.5)
+}
+
+func TestPRCurve(t *testing.T) {
+	precision, recall, _ := PRCurve(testScores, testLabels)
+
+	if !Equal(_roundData(precision, 6), Vector{1, 0.5, 0.666667, 0.5}) || !Equal(recall, Vector{0.5, 0.5, 1, 1}) {
+		t.Errorf("precision %v recall %v", precision, recall)
+	}
+
+	// 0.5 * 1 + 0.5 * 2/3
+	_testA(t, AveragePrecision(testScores, testLabels), 0.833333)
+}
+
+func TestBestThreshold(t *testing.T) {
+	threshold, f1 := BestThreshold(testScores, testLabels, F1Score)
+	_testAB(t, threshold, 0.35, f1, 0.8)
+
+	threshold, j := BestThreshold(Vector{0.9, 0.7, 0.6, 0.2, 0.1}, Vector{1, 1, 0, 0, 0}, YoudenJ)
+	_testAB(t, threshold, 0.7, j, 1)
+}
+
+func TestThresholdSingleClass(t *testing.T) {
+	// rates of the missing class would be 0 / 0
+	if fpr, tpr, _ := ROCCurve(testScores, Vector{1, 1, 1, 1}); fpr != nil || tpr != nil {
+		t.Errorf("fpr %v tpr %v vs expected nil without negatives", fpr, tpr)
+	}
+	if precision, recall, _ := PRCurve(testScores, Vector{0, 0, 0, 0}); precision != nil || recall != nil {
+		t.Errorf("precision %v recall %v vs expected nil without positives", precision, recall)
+	}
+	if ap := AveragePrecision(testScores, Vector{0, 0, 0, 0}); !math.IsNaN(ap) {
+		t.Errorf("average precision %f vs expected NaN without positives", ap)
+	}
+
+	for _, labels := range []Vector{{0, 0, 0, 0}, {1, 1, 1, 1}} {
+		threshold, f1 := BestThreshold(testScores, labels, F1Score)
+		if !math.IsNaN(threshold) || !math.IsNaN(f1) {
+			t.Errorf("%v: threshold %f score %f vs expected NaN", labels, threshold, f1)
+		}
+	}
+}
@@ -0,0 +1,337 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// NumPy .npy arrays, as saved by numpy.save, and .npz archives of
+// named arrays, as saved by numpy.savez. 1-d arrays map to vectors and
+// 0-d or 2-d arrays to matrices, of any float, int, uint or bool dtype.
+//
+
+//
+// Options for WriteNPY and WriteNPZ, the zero value writes
+// little-endian float64 arrays in C order
+//
+type NPYOptions struct {
+	// NumPy dtype, eg. "<f4" or ">i8", "<f8" when empty
+	Dtype string
+	// column major order
+	Fortran bool
+	// deflate the arrays of an .npz archive, as numpy.savez_compressed
+	Compress bool
+}
+
+//
+// Reported for input that isn't a .npy array
+//
+var ErrNPYFormat = errors.New("not a NumPy .npy file")
+
+var _npyMagic = []byte("\x93NUMPY")
+
+var _npyDescr = regexp.MustCompile(`'descr'\s*:\s*'([^']*)'`)
+var _npyFortran = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+var _npyShape = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+
+var _npyTypes = map[string]_numType{
+	"f8": _float64,
+	"f4": _float32,
+	"i1": _int8,
+	"u1": _uint8,
+	"b1": _uint8,
+	"i2": _int16,
+	"u2": _uint16,
+	"i4": _int32,
+	"u4": _uint32,
+	"i8": _int64,
+	"u8": _uint64,
+}
+
+//
+// Element type and byte order of a dtype
+//
+func _npyDtype(descr string) (typ _numType, order binary.ByteOrder, err error) {
+	if len(descr) != 3 {
+		return 0, nil, fmt.Errorf("npy: unsupported dtype %q", descr)
+	}
+
+	typ, ok := _npyTypes[descr[1:]]
+	if !ok {
+		return 0, nil, fmt.Errorf("npy: unsupported dtype %q", descr)
+	}
+
+	switch descr[0] {
+	case '<', '|':
+		order = binary.LittleEndian
+	case '>':
+		order = binary.BigEndian
+	case '=':
+		order = binary.NativeEndian
+	default:
+		return 0, nil, fmt.Errorf("npy: unsupported dtype %q", descr)
+	}
+
+	return typ, order, nil
+}
+
+//
+// Reads a .npy array as a Vector or a Matrix
+//
+func ReadNPY(r io.Reader) (data Data, err error) {
+	preamble := make([]byte, 8)
+	if _, err = io.ReadFull(r, preamble); err != nil || !bytes.Equal(preamble[:6], _npyMagic) {
+		return nil, ErrNPYFormat
+	}
+
+	// version 1 stores the header length in 2 bytes, later versions in 4
+	var size int
+	switch preamble[6] {
+	case 1:
+		buf := make([]byte, 2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, ErrNPYFormat
+		}
+		size = int(binary.LittleEndian.Uint16(buf))
+	case 2, 3:
+		buf := make([]byte, 4)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, ErrNPYFormat
+		}
+		size = int(binary.LittleEndian.Uint32(buf))
+	default:
+		return nil, fmt.Errorf("npy: version %d.%d: %w", preamble[6], preamble[7], ErrNPYFormat)
+	}
+
+	header := make([]byte, size)
+	if _, err = io.ReadFull(r, header); err != nil {
+		return nil, ErrNPYFormat
+	}
+
+	descr := _npyDescr.FindSubmatch(header)
+	fortran := _npyFortran.FindSubmatch(header)
+	shape := _npyShape.FindSubmatch(header)
+	if descr == nil || fortran == nil || shape == nil {
+		return nil, fmt.Errorf("npy: header %q: %w", header, ErrNPYFormat)
+	}
+
+	typ, order, err := _npyDtype(string(descr[1]))
+	if err != nil {
+		return nil, err
+	}
+
+	var dims []int
+	for _, dim := range strings.Split(string(shape[1]), ",") {
+		if dim = strings.TrimSpace(dim); dim == "" {
+			continue
+		}
+		n, err := strconv.Atoi(dim)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("npy: shape %q: %w", shape[1], ErrNPYFormat)
+		}
+		dims = append(dims, n)
+	}
+	if len(dims) > 2 {
+		return nil, fmt.Errorf("npy: unsupported %d-d array", len(dims))
+	}
+
+	// the shape isn't trusted to size allocations, the data is read
+	// up to its size and the products are checked for overflow
+	count := 1
+	for _, n := range dims {
+		if n > 0 && count > math.MaxInt64/typ.size()/n {
+			return nil, fmt.Errorf("npy: shape %q: %w", shape[1], ErrNPYFormat)
+		}
+		count *= n
+	}
+	if len(dims) == 2 && dims[1] == 0 && dims[0] > _maxEmptyRows {
+		return nil, fmt.Errorf("npy: shape %q: %w", shape[1], ErrNPYFormat)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(r, int64(count*typ.size())))
+	if err != nil || len(raw) != count*typ.size() {
+		return nil, fmt.Errorf("npy: data: %w", io.ErrUnexpectedEOF)
+	}
+	values := _decodeNumbers(raw, typ, order)
+
+	switch len(dims) {
+	case 0:
+		return Matrix{{values[0]}}, nil
+	case 1:
+		return Vector(values), nil
+	}
+
+	rows, cols := dims[0], dims[1]
+	matrix := NewMatrix(rows, cols)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			if string(fortran[1]) == "True" {
+				matrix[row][col] = values[col*rows+row]
+			} else {
+				matrix[row][col] = values[row*cols+col]
+			}
+		}
+	}
+
+	return matrix, nil
+}
+
+//
+// Writes a Vector as a 1-d or a Matrix as a 2-d .npy array
+//
+func WriteNPY(w io.Writer, data Data, opts *NPYOptions) (err error) {
+	if opts == nil {
+		opts = &NPYOptions{}
+	}
+	descr := opts.Dtype
+	if descr == "" {
+		descr = "<f8"
+	}
+	typ, order, err := _npyDtype(descr)
+	if err != nil {
+		return err
+	}
+
+	data, _ = _undense(data)
+	var shape string
+	var values []float64
+	switch data.(type) {
+	case Vector:
+		values = data.(Vector)
+		shape = fmt.Sprintf("(%d,)", len(values))
+	case Matrix:
+		matrix := data.(Matrix)
+		if err = _checkRows("npy", matrix); err != nil {
+			return err
+		}
+		rows, cols := Size(matrix)
+		shape = fmt.Sprintf("(%d, %d)", rows, cols)
+
+		values = make([]float64, 0, rows*cols)
+		if opts.Fortran {
+			for col := 0; col < cols; col++ {
+				for row := 0; row < rows; row++ {
+					values = append(values, matrix[row][col])
+				}
+			}
+		} else {
+			for _, row := range matrix {
+				values = append(values, row...)
+			}
+		}
+	default:
+		return &TypeError{Op: "npy", Type1: reflect.TypeOf(data)}
+	}
+
+	fortran := "False"
+	if opts.Fortran {
+		fortran = "True"
+	}
+	header := fmt.Sprintf("{'descr': '%s', 'fortran_order': %s, 'shape': %s, }", descr, fortran, shape)
+
+	// pad the preamble with spaces and a newline to a multiple of 64 bytes
+	preamble := len(_npyMagic) + 4
+	version := byte(1)
+	if preamble+len(header)+1 > 0xffff {
+		preamble += 2
+		version = 2
+	}
+	header += strings.Repeat(" ", (64-(preamble+len(header)+1)%64)%64) + "\n"
+
+	var buf bytes.Buffer
+	buf.Write(_npyMagic)
+	buf.Write([]byte{version, 0})
+	if version == 1 {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(header)))
+	}
+	buf.WriteString(header)
+
+	if _, err = w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	_, err = w.Write(_encodeNumbers(values, typ, order))
+
+	return err
+}
+
+//
+// Reads the named arrays of a .npz archive of size bytes
+//
+func ReadNPZ(r io.ReaderAt, size int64) (arrays map[string]Data, err error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("npz: %w", err)
+	}
+
+	arrays = make(map[string]Data)
+	for _, file := range archive.File {
+		if !strings.HasSuffix(file.Name, ".npy") {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("npz: %s: %w", file.Name, err)
+		}
+		data, err := ReadNPY(reader)
+		reader.Close()
+		if err != nil {
+			return nil, fmt.Errorf("npz: %s: %w", file.Name, err)
+		}
+
+		arrays[strings.TrimSuffix(file.Name, ".npy")] = data
+	}
+
+	return arrays, nil
+}
+
+//
+// Writes the named arrays as a .npz archive, loadable with numpy.load,
+// in name order
+//
+func WriteNPZ(w io.Writer, arrays map[string]Data, opts *NPYOptions) (err error) {
+	if opts == nil {
+		opts = &NPYOptions{}
+	}
+
+	names := make([]string, 0, len(arrays))
+	for name := range arrays {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	method := zip.Store
+	if opts.Compress {
+		method = zip.Deflate
+	}
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		writer, err := archive.CreateHeader(&zip.FileHeader{Name: name + ".npy", Method: method})
+		if err != nil {
+			return fmt.Errorf("npz: %s: %w", name, err)
+		}
+		if err = WriteNPY(writer, arrays[name], opts); err != nil {
+			return fmt.Errorf("npz: %s: %w", name, err)
+		}
+	}
+
+	return archive.Close()
+}
@@ -0,0 +1,140 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+//
+// Builds a version 1.0 .npy file as numpy.save writes it
+//
+func _npyFixture(header string, data []byte) []byte {
+	header += strings.Repeat(" ", 64-(10+len(header)+1)%64) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString("\x93NUMPY\x01\x00")
+	binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	buf.WriteString(header)
+	buf.Write(data)
+
+	return buf.Bytes()
+}
+
+func TestReadNPY(t *testing.T) {
+	// numpy.array([[1.5, 2], [3, 4]], dtype='>f8', order='F')
+	raw := _encodeNumbers([]float64{1.5, 3, 2, 4}, _float64, binary.BigEndian)
+	data, err := ReadNPY(bytes.NewReader(_npyFixture("{'descr': '>f8', 'fortran_order': True, 'shape': (2, 2), }", raw)))
+	exp := Matrix{{1.5, 2}, {3, 4}}
+	if err != nil || !Equal(data, exp) {
+		t.Errorf("%v != %v (%v)", data, exp, err)
+	}
+
+	// numpy.arange(3, dtype='<u2')
+	raw = _encodeNumbers([]float64{0, 1, 2}, _uint16, binary.LittleEndian)
+	data, err = ReadNPY(bytes.NewReader(_npyFixture("{'descr': '<u2', 'fortran_order': False, 'shape': (3,), }", raw)))
+	if err != nil || !Equal(data, Vector{0, 1, 2}) {
+		t.Errorf("%v != %v (%v)", data, Vector{0, 1, 2}, err)
+	}
+
+	_, err = ReadNPY(bytes.NewReader(_npyFixture("{'descr': '<f8', 'fortran_order': False, 'shape': (2, 2, 2), }", nil)))
+	if err == nil {
+		t.Errorf("3-d array vs expected error")
+	}
+
+	// shapes far beyond the data must not be allocated up front
+	for _, shape := range []string{"(4000000000, 4000000000)", "(4000000000, 0)", "(4, 1000000000)", "(1000000000000,)"} {
+		_, err = ReadNPY(bytes.NewReader(_npyFixture("{'descr': '<f8', 'fortran_order': False, 'shape': "+shape+", }", raw)))
+		if err == nil {
+			t.Errorf("%s vs expected error", shape)
+		}
+	}
+
+	_, err = ReadNPY(strings.NewReader("PK\x03\x04"))
+	if !errors.Is(err, ErrNPYFormat) {
+		t.Errorf("%v is not %v", err, ErrNPYFormat)
+	}
+}
+
+func TestWriteReadNPY(t *testing.T) {
+	matrix := Matrix{{1, -2, 3}, {4, 5, -6}}
+
+	for _, opts := range []*NPYOptions{
+		nil,
+		{Fortran: true},
+		{Dtype: ">f8"},
+		{Dtype: "<f4", Fortran: true},
+		{Dtype: ">i2"},
+		{Dtype: "<i8"},
+		{Dtype: "|i1"},
+	} {
+		var buf bytes.Buffer
+		if err := WriteNPY(&buf, matrix, opts); err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := ReadNPY(&buf)
+		if err != nil || !Equal(data, matrix) {
+			t.Errorf("%+v: %v != %v (%v)", opts, data, matrix, err)
+		}
+	}
+
+	if err := WriteNPY(&bytes.Buffer{}, matrix, &NPYOptions{Dtype: "<c16"}); err == nil {
+		t.Errorf("complex dtype vs expected error")
+	}
+
+	var dimErr *DimensionError
+	if err := WriteNPY(&bytes.Buffer{}, Matrix{{1, 2}, {3}}, &NPYOptions{Fortran: true}); !errors.As(err, &dimErr) {
+		t.Errorf("%v vs expected a dimension error for a ragged matrix", err)
+	}
+}
+
+func TestWriteNPYExact(t *testing.T) {
+	vector := Vector{math.Pi, 1e-300, math.Inf(-1), 0.1}
+
+	var buf bytes.Buffer
+	WriteNPY(&buf, vector, nil)
+	if buf.Len() != 128+len(vector)*8 {
+		t.Errorf("%d bytes vs expected a 128 byte preamble", buf.Len())
+	}
+
+	data, err := ReadNPY(&buf)
+	if err != nil || !Equal(data, vector) {
+		t.Errorf("%v != %v (%v)", data, vector, err)
+	}
+}
+
+func TestWriteReadNPZ(t *testing.T) {
+	arrays := map[string]Data{
+		"X":     Matrix{{1, 2}, {3, 4}, {5, 6}},
+		"y":     Vector{0, 1, 1},
+		"theta": DenseFromMatrix(Matrix{{0.25}, {-0.5}}),
+	}
+
+	for _, compress := range []bool{false, true} {
+		var buf bytes.Buffer
+		if err := WriteNPZ(&buf, arrays, &NPYOptions{Compress: compress}); err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := ReadNPZ(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(loaded) != len(arrays) {
+			t.Errorf("%d arrays vs expected %d", len(loaded), len(arrays))
+		}
+		for name, data := range arrays {
+			if !Equal(loaded[name], data) {
+				t.Errorf("%s: %v != %v", name, loaded[name], data)
+			}
+		}
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//
+// Octave text format, as written by save -text:
+//
+//   # name: X
+//   # type: matrix
+//   # rows: 2
+//   # columns: 3
+//    1 2 3
+//    4 5 6
+//
+// Matrix, scalar and their bool variants are handled, scalars are
+// read as 1x1 matrices.
+//
+
+//
+// Line reader tracking the line number for errors
+//
+type _octaveReader struct {
+	reader *bufio.Reader
+	line   int
+}
+
+//
+// Next non-blank line, io.EOF at the end of the input
+//
+func (self *_octaveReader) next() (text string, err error) {
+	for {
+		text, err = self.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || text == "") {
+			return "", err
+		}
+		self.line++
+
+		text = strings.TrimSpace(text)
+		if text != "" {
+			return text, nil
+		}
+	}
+}
+
+//
+// Reads rows lines of cols values. The sizes come from the file, so
+// each row is allocated only once its line is read.
+//
+func (self *_octaveReader) values(rows int, cols int) (matrix Matrix, err error) {
+	matrix = NewEmptyMatrix(0)
+	for row := 0; row < rows; row++ {
+		text, err := self.next()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != cols {
+			return nil, &ParseError{Row: self.line, Col: len(fields) + 1, Err: ErrFieldCount}
+		}
+		values := NewVector(cols)
+		for col, field := range fields {
+			values[col], err = _parseCell(field, []string{"NA"})
+			if err != nil {
+				return nil, &ParseError{Row: self.line, Col: col + 1, Value: field, Err: err}
+			}
+		}
+		matrix = append(matrix, values)
+	}
+
+	return matrix, nil
+}
+
+//
+// Loads the named matrices of an Octave / MATLAB text file
+//
+func LoadOctaveText(r io.Reader) (vars map[string]Matrix, err error) {
+	reader := &_octaveReader{reader: bufio.NewReader(r)}
+	vars = make(map[string]Matrix)
+
+	name, typ := "", ""
+	rows := -1
+	for {
+		text, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !strings.HasPrefix(text, "#") {
+			return nil, &ParseError{Row: reader.line, Col: 1, Value: text, Err: fmt.Errorf("data outside of a variable")}
+		}
+
+		key, value, ok := strings.Cut(strings.TrimSpace(text[1:]), ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		if key == "name" {
+			if !_varName.MatchString(value) {
+				return nil, &ParseError{Row: reader.line, Col: 1, Value: value, Err: fmt.Errorf("invalid variable name")}
+			}
+			name, typ, rows = value, "", -1
+			continue
+		}
+		if name == "" {
+			// comments ahead of the first variable, eg. "# Created by Octave"
+			continue
+		}
+
+		switch key {
+		case "type":
+			typ = strings.TrimPrefix(value, "global ")
+			switch typ {
+			case "scalar", "bool":
+				vars[name], err = reader.values(1, 1)
+				if err != nil {
+					return nil, err
+				}
+			case "matrix", "bool matrix":
+			default:
+				return nil, fmt.Errorf("octave: %s: unsupported type %q", name, typ)
+			}
+		case "rows", "columns":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, &ParseError{Row: reader.line, Col: 1, Value: value, Err: fmt.Errorf("invalid %s", key)}
+			}
+			if key == "rows" {
+				rows = n
+			} else if rows >= 0 {
+				vars[name], err = reader.values(rows, n)
+				if err != nil {
+					return nil, err
+				}
+			}
+		case "ndims":
+			return nil, fmt.Errorf("octave: %s: unsupported N-d %s", name, typ)
+		}
+	}
+
+	return vars, nil
+}
+
+//
+// Formats a value so that it reads back exactly
+//
+func _octaveValue(val float64) string {
+	switch {
+	case math.IsInf(val, 1):
+		return "Inf"
+	case math.IsInf(val, -1):
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+//
+// Saves the named matrices in the Octave / MATLAB text format,
+// loadable with load in either, in name order
+//
+func SaveOctaveText(w io.Writer, vars map[string]Matrix) (err error) {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		if !_varName.MatchString(name) {
+			return fmt.Errorf("octave: invalid variable name %q", name)
+		}
+		if err = _checkRows("octave", vars[name]); err != nil {
+			return err
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	writer := bufio.NewWriter(w)
+	fmt.Fprintf(writer, "# Created by goml\n")
+	for _, name := range names {
+		matrix := vars[name]
+		rows, cols := Size(matrix)
+
+		fmt.Fprintf(writer, "# name: %s\n# type: matrix\n# rows: %d\n# columns: %d\n", name, rows, cols)
+		for _, row := range matrix {
+			for _, val := range row {
+				fmt.Fprintf(writer, " %s", _octaveValue(val))
+			}
+			writer.WriteString("\n")
+		}
+		writer.WriteString("\n\n")
+	}
+
+	return writer.Flush()
+}
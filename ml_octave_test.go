@@ -0,0 +1,118 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+const testOctave = `# Created by Octave 8.4.0, Tue Oct 13 09:15:02 2026 UTC <ml@example>
+# name: X
+# type: matrix
+# rows: 3
+# columns: 2
+ 6.1101 17.592
+ 5.5277 9.1302
+ 8.5186 13.662
+
+
+# name: alpha
+# type: scalar
+0.01
+
+
+# name: flags
+# type: bool matrix
+# rows: 1
+# columns: 3
+ 1 0 1
+
+
+`
+
+func TestLoadOctaveText(t *testing.T) {
+	vars, err := LoadOctaveText(strings.NewReader(testOctave))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := Matrix{{6.1101, 17.592}, {5.5277, 9.1302}, {8.5186, 13.662}}
+	if !Equal(vars["X"], exp) {
+		t.Errorf("%v != %v", vars["X"], exp)
+	}
+	if !Equal(vars["alpha"], Matrix{{0.01}}) {
+		t.Errorf("%v != %v", vars["alpha"], Matrix{{0.01}})
+	}
+	if !Equal(vars["flags"], Matrix{{1, 0, 1}}) {
+		t.Errorf("%v != %v", vars["flags"], Matrix{{1, 0, 1}})
+	}
+}
+
+func TestSaveOctaveText(t *testing.T) {
+	vars := map[string]Matrix{
+		"theta": {{-3.6302914394043597}, {1.166362350335582}},
+		"odd":   {{math.Inf(1), math.Inf(-1), 1e-300}},
+	}
+
+	var buf bytes.Buffer
+	if err := SaveOctaveText(&buf, vars); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadOctaveText(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, matrix := range vars {
+		if !Equal(loaded[name], matrix) {
+			t.Errorf("%s: %v != %v", name, loaded[name], matrix)
+		}
+	}
+
+	buf.Reset()
+	SaveOctaveText(&buf, map[string]Matrix{"empty": {}})
+	loaded, err = LoadOctaveText(&buf)
+	if empty, ok := loaded["empty"]; err != nil || !ok || len(empty) != 0 {
+		t.Errorf("%v vs expected an empty matrix (%v)", empty, err)
+	}
+
+	if err := SaveOctaveText(&buf, map[string]Matrix{"1x": {{1}}}); err == nil {
+		t.Errorf("invalid name vs expected error")
+	}
+
+	var dimErr *DimensionError
+	if err := SaveOctaveText(&buf, map[string]Matrix{"x": {{1, 2}, {3}}}); !errors.As(err, &dimErr) {
+		t.Errorf("%v vs expected a dimension error for a ragged matrix", err)
+	}
+}
+
+func TestLoadOctaveTextErrors(t *testing.T) {
+	_, err := LoadOctaveText(strings.NewReader("# name: X\n# type: matrix\n# rows: 2\n# columns: 2\n 1 2\n 3 x\n"))
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Row != 6 || parseErr.Col != 2 {
+		t.Errorf("%v vs expected a parse error on line 6, column 2", err)
+	}
+
+	_, err = LoadOctaveText(strings.NewReader("# name: X\n# type: matrix\n# rows: 2\n# columns: 2\n 1 2\n"))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("%v is not %v", err, io.ErrUnexpectedEOF)
+	}
+
+	// sizes far beyond the data aren't allocated up front
+	_, err = LoadOctaveText(strings.NewReader("# name: X\n# type: matrix\n# rows: 100000000\n# columns: 100000000\n"))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("%v is not %v", err, io.ErrUnexpectedEOF)
+	}
+
+	_, err = LoadOctaveText(strings.NewReader("# name: s\n# type: string\n# elements: 1\n"))
+	if err == nil {
+		t.Errorf("string variable vs expected error")
+	}
+}
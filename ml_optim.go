@@ -0,0 +1,326 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"math"
+)
+
+//
+// Update rule of an iterative learner. Step moves params, in place,
+// against the gradient of the cost. Optimizers that keep a running
+// state per parameter also have a Reset method to start over, which
+// the learners call before each fit. Those with a learning rate have
+// Rate and SetRate methods, which let SGD follow a Schedule.
+//
+type Optimizer interface {
+	Step(params Matrix, grads Matrix)
+}
+
+//
+// Resets the running state of opt, if it keeps one
+//
+func _resetOptimizer(opt Optimizer) {
+	if stateful, ok := opt.(interface{ Reset() }); ok {
+		stateful.Reset()
+	}
+}
+
+//
+// Optimizer with a learning rate that a Schedule can drive
+//
+type _rated interface {
+	Rate() float64
+	SetRate(alpha float64)
+}
+
+//
+// Zeroed state matrix shaped like params, kept while the shape holds
+//
+func _optimState(state Matrix, params Matrix) Matrix {
+	rows, cols := Size(params)
+	if rows2, cols2 := Size(state); state == nil || rows != rows2 || cols != cols2 {
+		return NewMatrix(rows, cols)
+	}
+
+	return state
+}
+
+//
+// Plain gradient descent
+// p = p - alpha * g
+//
+type GD struct {
+	Alpha float64 // learning rate
+}
+
+//
+// Constructor
+//
+func NewGD(alpha float64) *GD {
+	return &GD{Alpha: alpha}
+}
+
+func (self *GD) Step(params Matrix, grads Matrix) {
+	for i, row := range grads {
+		for j, g := range row {
+			params[i][j] -= self.Alpha * g
+		}
+	}
+}
+
+func (self *GD) Rate() float64 {
+	return self.Alpha
+}
+
+func (self *GD) SetRate(alpha float64) {
+	self.Alpha = alpha
+}
+
+//
+// Gradient descent with momentum, accumulating a velocity along
+// consistent gradients. Nesterov momentum steps from the look-ahead
+// position instead.
+// v = beta * v + g
+// p = p - alpha * v                (classical)
+// p = p - alpha * (g + beta * v)   (Nesterov)
+//
+type Momentum struct {
+	Alpha    float64 // learning rate
+	Beta     float64 // velocity decay, eg. 0.9
+	Nesterov bool
+	velocity Matrix
+}
+
+//
+// Constructor
+//
+func NewMomentum(alpha float64, beta float64, nesterov bool) *Momentum {
+	return &Momentum{Alpha: alpha, Beta: beta, Nesterov: nesterov}
+}
+
+func (self *Momentum) Step(params Matrix, grads Matrix) {
+	self.velocity = _optimState(self.velocity, params)
+
+	for i, row := range grads {
+		for j, g := range row {
+			v := self.Beta*self.velocity[i][j] + g
+			self.velocity[i][j] = v
+
+			if self.Nesterov {
+				params[i][j] -= self.Alpha * (g + self.Beta*v)
+			} else {
+				params[i][j] -= self.Alpha * v
+			}
+		}
+	}
+}
+
+func (self *Momentum) Reset() {
+	self.velocity = nil
+}
+
+func (self *Momentum) Rate() float64 {
+	return self.Alpha
+}
+
+func (self *Momentum) SetRate(alpha float64) {
+	self.Alpha = alpha
+}
+
+//
+// AdaGrad, scaling the rate of each parameter down by its
+// accumulated squared gradients
+// c = c + g .^ 2
+// p = p - alpha * g ./ (sqrt(c) + epsilon)
+//
+type AdaGrad struct {
+	Alpha   float64 // learning rate
+	Epsilon float64 // guards the division, eg. 1e-8
+	cache   Matrix
+}
+
+//
+// Constructor
+//
+func NewAdaGrad(alpha float64) *AdaGrad {
+	return &AdaGrad{Alpha: alpha, Epsilon: 1e-8}
+}
+
+func (self *AdaGrad) Step(params Matrix, grads Matrix) {
+	self.cache = _optimState(self.cache, params)
+
+	for i, row := range grads {
+		for j, g := range row {
+			self.cache[i][j] += g * g
+			params[i][j] -= self.Alpha * g / (math.Sqrt(self.cache[i][j]) + self.Epsilon)
+		}
+	}
+}
+
+func (self *AdaGrad) Reset() {
+	self.cache = nil
+}
+
+func (self *AdaGrad) Rate() float64 {
+	return self.Alpha
+}
+
+func (self *AdaGrad) SetRate(alpha float64) {
+	self.Alpha = alpha
+}
+
+//
+// RMSProp, AdaGrad with a moving average of the squared gradients
+// so the rate doesn't decay to 0
+// c = decay * c + (1 - decay) * g .^ 2
+// p = p - alpha * g ./ (sqrt(c) + epsilon)
+//
+type RMSProp struct {
+	Alpha   float64 // learning rate
+	Decay   float64 // moving average decay, eg. 0.9
+	Epsilon float64 // guards the division, eg. 1e-8
+	cache   Matrix
+}
+
+//
+// Constructor
+//
+func NewRMSProp(alpha float64, decay float64) *RMSProp {
+	return &RMSProp{Alpha: alpha, Decay: decay, Epsilon: 1e-8}
+}
+
+func (self *RMSProp) Step(params Matrix, grads Matrix) {
+	self.cache = _optimState(self.cache, params)
+
+	for i, row := range grads {
+		for j, g := range row {
+			self.cache[i][j] = self.Decay*self.cache[i][j] + (1-self.Decay)*g*g
+			params[i][j] -= self.Alpha * g / (math.Sqrt(self.cache[i][j]) + self.Epsilon)
+		}
+	}
+}
+
+func (self *RMSProp) Reset() {
+	self.cache = nil
+}
+
+func (self *RMSProp) Rate() float64 {
+	return self.Alpha
+}
+
+func (self *RMSProp) SetRate(alpha float64) {
+	self.Alpha = alpha
+}
+
+//
+// Adam, moving averages of the gradients and of their squares,
+// corrected for their bias towards 0 over the first steps
+// m = beta1 * m + (1 - beta1) * g
+// v = beta2 * v + (1 - beta2) * g .^ 2
+// p = p - alpha * (m / (1 - beta1 ^ t)) ./ (sqrt(v / (1 - beta2 ^ t)) + epsilon)
+//
+type Adam struct {
+	Alpha   float64 // learning rate
+	Beta1   float64 // gradient average decay, eg. 0.9
+	Beta2   float64 // squared gradient average decay, eg. 0.999
+	Epsilon float64 // guards the division, eg. 1e-8
+	m       Matrix
+	v       Matrix
+	t       int
+}
+
+//
+// Constructor with the usual decays
+//
+func NewAdam(alpha float64) *Adam {
+	return &Adam{Alpha: alpha, Beta1: 0.9, Beta2: 0.999, Epsilon: 1e-8}
+}
+
+func (self *Adam) Step(params Matrix, grads Matrix) {
+	self.m = _optimState(self.m, params)
+	self.v = _optimState(self.v, params)
+	self.t++
+
+	correct1 := 1 - math.Pow(self.Beta1, float64(self.t))
+	correct2 := 1 - math.Pow(self.Beta2, float64(self.t))
+	for i, row := range grads {
+		for j, g := range row {
+			self.m[i][j] = self.Beta1*self.m[i][j] + (1-self.Beta1)*g
+			self.v[i][j] = self.Beta2*self.v[i][j] + (1-self.Beta2)*g*g
+			params[i][j] -= self.Alpha * (self.m[i][j] / correct1) / (math.Sqrt(self.v[i][j]/correct2) + self.Epsilon)
+		}
+	}
+}
+
+func (self *Adam) Reset() {
+	self.m, self.v, self.t = nil, nil, 0
+}
+
+func (self *Adam) Rate() float64 {
+	return self.Alpha
+}
+
+func (self *Adam) SetRate(alpha float64) {
+	self.Alpha = alpha
+}
+
+//
+// Adam with decoupled weight decay, which shrinks every parameter
+// (the bias included) rather than adding an L2 term to the gradient
+// p = p - alpha * weightDecay * p, then the Adam step
+//
+type AdamW struct {
+	Adam
+	WeightDecay float64
+}
+
+//
+// Constructor with the usual decays
+//
+func NewAdamW(alpha float64, weightDecay float64) *AdamW {
+	return &AdamW{Adam: *NewAdam(alpha), WeightDecay: weightDecay}
+}
+
+func (self *AdamW) Step(params Matrix, grads Matrix) {
+	for _, row := range params {
+		for j := range row {
+			row[j] -= self.Alpha * self.WeightDecay * row[j]
+		}
+	}
+
+	self.Adam.Step(params, grads)
+}
+
+//
+// Gradient descent on the linear regression cost with any optimizer,
+// keeping the last theta that lowered the cost once a step fails to.
+// Momentum and adaptive optimizers may overshoot early on, see SGD to
+// run a fixed number of epochs instead.
+//
+func GradientDescentWith(X Matrix, y Matrix, intheta Matrix, opt Optimizer, num_iters int) (theta Matrix, J_history Vector) {
+	J_history = NewEmptyVector()
+
+	theta = _copyM(intheta)
+
+	_resetOptimizer(opt)
+	Jc, grad := _lrCost(X, y, theta)
+	for i := 0; i < num_iters; i++ {
+		next := _copyM(theta)
+		opt.Step(next, grad)
+
+		// the cost of this step is the starting cost of the next
+		Jn, nextGrad := _lrCost(X, y, next)
+
+		if Jn == Jc || Jn > Jc {
+			break
+		}
+
+		J_history = append(J_history, Jn)
+		theta, grad, Jc = next, nextGrad, Jn
+	}
+
+	return theta, J_history
+}
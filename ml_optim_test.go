@@ -0,0 +1,138 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"math"
+	"testing"
+)
+
+//
+// Parameters after each step of opt on the same gradient
+//
+func _testSteps(opt Optimizer, grads Matrix, steps int) (history []Matrix) {
+	params := Matrix{{1}, {1}}
+	for i := 0; i < steps; i++ {
+		opt.Step(params, grads)
+		history = append(history, _roundData(params, 6).(Matrix))
+	}
+
+	return history
+}
+
+func TestOptimizerSteps(t *testing.T) {
+	grads := Matrix{{0.5}, {-2}}
+
+	tests := []struct {
+		name string
+		opt  Optimizer
+		exp  []Matrix
+	}{
+		{"gd", NewGD(0.1), []Matrix{{{0.95}, {1.2}}, {{0.9}, {1.4}}}},
+		// v = 0.5, 0.95 / -2, -3.8
+		{"momentum", NewMomentum(0.1, 0.9, false), []Matrix{{{0.95}, {1.2}}, {{0.855}, {1.58}}}},
+		// g + 0.9 * v = 0.95, 1.355 / -3.8, -5.42
+		{"nesterov", NewMomentum(0.1, 0.9, true), []Matrix{{{0.905}, {1.38}}, {{0.7695}, {1.922}}}},
+		// the first step is alpha * sign(g), then 1 / sqrt(2) of it
+		{"adagrad", NewAdaGrad(0.1), []Matrix{{{0.9}, {1.1}}, {{0.829289}, {1.170711}}}},
+		// 1 / sqrt(0.1) then 1 / sqrt(0.19) of alpha * sign(g)
+		{"rmsprop", NewRMSProp(0.1, 0.9), []Matrix{{{0.683772}, {1.316228}}, {{0.454357}, {1.545643}}}},
+		// bias correction makes each step alpha * sign(g) for a constant g
+		{"adam", NewAdam(0.1), []Matrix{{{0.9}, {1.1}}, {{0.8}, {1.2}}}},
+	}
+
+	for _, test := range tests {
+		history := _testSteps(test.opt, grads, 2)
+		for i, exp := range test.exp {
+			if !Equal(history[i], exp) {
+				t.Errorf("%s step %d: %v != %v", test.name, i+1, history[i], exp)
+			}
+		}
+
+		// a reset optimizer repeats its first step
+		_resetOptimizer(test.opt)
+		if history = _testSteps(test.opt, grads, 1); !Equal(history[0], test.exp[0]) {
+			t.Errorf("%s after reset: %v != %v", test.name, history[0], test.exp[0])
+		}
+	}
+}
+
+func TestAdamW(t *testing.T) {
+	// without a gradient only the decoupled decay moves the parameters
+	history := _testSteps(NewAdamW(0.1, 0.5), Matrix{{0}, {0}}, 2)
+	if !Equal(history[1], Matrix{{0.9025}, {0.9025}}) {
+		t.Errorf("%v != %v", history[1], Matrix{{0.9025}, {0.9025}})
+	}
+}
+
+func TestOptimizersSGD(t *testing.T) {
+	for name, opt := range map[string]Optimizer{
+		"gd":       NewGD(0.1),
+		"momentum": NewMomentum(0.05, 0.9, false),
+		"nesterov": NewMomentum(0.05, 0.9, true),
+		"adagrad":  NewAdaGrad(0.5),
+		"rmsprop":  NewRMSProp(0.01, 0.9),
+		"adam":     NewAdam(0.05),
+	} {
+		sgd := NewSGD(0, 4, 300)
+		sgd.RNG = NewSeededRNG(1)
+		sgd.Optimizer = opt
+
+		theta, _ := sgd.Minimize(testSGDX, testSGDY, NewMatrix(2, 1))
+		if math.Abs(theta[0][0]-1) > 0.05 || math.Abs(theta[1][0]-2) > 0.05 {
+			t.Errorf("%s: %v vs expected ~[[1] [2]]", name, theta)
+		}
+	}
+}
+
+func TestOptimizerSchedule(t *testing.T) {
+	opt := NewMomentum(0.05, 0.9, false)
+	sgd := NewSGD(0, 4, 20)
+	sgd.RNG = NewSeededRNG(1)
+	sgd.Optimizer = opt
+
+	// the optimizer stops moving once the schedule drops its rate to 0
+	sgd.Schedule = func(alpha float64, epoch int) float64 {
+		if epoch >= 5 {
+			return 0
+		}
+		return alpha
+	}
+
+	_, J_history := sgd.Minimize(testSGDX, testSGDY, NewMatrix(2, 1))
+	if J_history[3] == J_history[4] || J_history[5] != J_history[19] {
+		t.Errorf("%v vs expected a constant cost after epoch 4", J_history)
+	}
+	if opt.Alpha != 0.05 {
+		t.Errorf("rate %f vs expected %f restored", opt.Alpha, 0.05)
+	}
+}
+
+func TestGradientDescentWith(t *testing.T) {
+	theta, J_history := GradientDescentWith(testSGDX, testSGDY, NewMatrix(2, 1), NewGD(0.1), 50)
+	gdTheta, gdJ_history := GradientDescent(testSGDX, testSGDY, NewMatrix(2, 1), 0.1, 50)
+	if !Equal(theta, gdTheta) || !Equal(J_history, gdJ_history) {
+		t.Errorf("%v != %v", theta, gdTheta)
+	}
+}
+
+func TestTrainerOptimizer(t *testing.T) {
+	linear := NewLinearRegression(0, 2000)
+	linear.Optimizer = NewAdaGrad(0.5)
+	linear.Fit(testSGDX, testSGDY)
+	if !Equal(_roundData(linear.Theta, 2), Matrix{{1}, {2}}) {
+		t.Errorf("%v vs expected ~[[1] [2]]", linear.Theta)
+	}
+
+	X := Join(Ones(6, 1), Matrix{{-3}, {-2}, {-1}, {1}, {2}, {3}}).(Matrix)
+	y := Matrix{{0}, {0}, {0}, {1}, {1}, {1}}
+
+	logistic := NewLogisticRegression(0, 0, 100)
+	logistic.Optimizer = NewAdam(0.1)
+	logistic.Fit(X, y)
+	if !Equal(logistic.Predict(X), y) {
+		t.Errorf("%v != %v", logistic.Predict(X), y)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+//
+// Random number generator over a rand.Source. Every random function in
+// the package has a variant taking an *RNG, so an experiment can be
+// reproduced by seeding its own generator. An RNG is safe for
+// concurrent use.
+//
+type RNG struct {
+	lock sync.Mutex
+	rand *rand.Rand
+}
+
+//
+// Constructor
+//
+func NewRNG(source rand.Source) *RNG {
+	r := &RNG{rand: rand.New(source)}
+
+	return r
+}
+
+//
+// Constructor for a generator with a fixed seed
+//
+func NewSeededRNG(seed int64) *RNG {
+	return NewRNG(rand.NewSource(seed))
+}
+
+//
+// Generator used by the functions that don't take an *RNG. It is
+// seeded from the clock once, call Seed to make runs reproducible.
+//
+var DefaultRNG = NewSeededRNG(time.Now().UTC().UnixNano())
+
+//
+// Seeds the default generator
+//
+func Seed(seed int64) {
+	DefaultRNG.Seed(seed)
+}
+
+//
+// Falls back to the default generator for a nil *RNG
+//
+func _rng(rng *RNG) *RNG {
+	if rng == nil {
+		return DefaultRNG
+	}
+
+	return rng
+}
+
+//
+// Reseeds the generator
+//
+func (self *RNG) Seed(seed int64) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.rand.Seed(seed)
+}
+
+//
+// Uniform value in [0.0, 1.0)
+//
+func (self *RNG) Float64() float64 {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	return self.rand.Float64()
+}
+
+//
+// Uniform integer in [0, n)
+//
+func (self *RNG) Intn(n int) int {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	return self.rand.Intn(n)
+}
+
+//
+// Uniform value in [min, max)
+//
+func (self *RNG) Uniform(min float64, max float64) float64 {
+	return min + self.Float64()*(max-min)
+}
+
+//
+// Normally distributed value with the given mean and standard deviation
+//
+func (self *RNG) Normal(mean float64, std float64) float64 {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	return mean + self.rand.NormFloat64()*std
+}
+
+//
+// Random permutation of the integers [0, n)
+//
+func (self *RNG) Perm(n int) []int {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	return self.rand.Perm(n)
+}
+
+//
+// Shuffles n elements through the swap function
+//
+func (self *RNG) Shuffle(n int, swap func(i int, j int)) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.rand.Shuffle(n, swap)
+}
@@ -0,0 +1,62 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestRandWithReproducible(t *testing.T) {
+	x := RandWith(NewSeededRNG(11), 3, 4)
+	y := RandWith(NewSeededRNG(11), 3, 4)
+	if !Equal(x, y) {
+		t.Errorf("%v != %v", x, y)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	Seed(5)
+	x := Rand(2, 3)
+	Seed(5)
+	y := Rand(2, 3)
+	if !Equal(x, y) {
+		t.Errorf("%v != %v", x, y)
+	}
+}
+
+func TestRNGUniform(t *testing.T) {
+	rng := NewSeededRNG(1)
+	for i := 0; i < 1000; i++ {
+		v := rng.Uniform(-2.0, 3.0)
+		if v < -2.0 || v >= 3.0 {
+			t.Errorf("%f vs expected range %f:%f", v, -2.0, 3.0)
+			break
+		}
+	}
+}
+
+func TestRNGNormal(t *testing.T) {
+	x := Unroll(RandnWith(NewSeededRNG(1), 100, 100))
+	v := []float64(x)
+
+	if Round(Mean(&v), 1) != 0.0 {
+		t.Errorf("mean %f vs expected ~%f", Mean(&v), 0.0)
+	}
+	if Round(StandardDeviation(&v), 1) != 1.0 {
+		t.Errorf("std %f vs expected ~%f", StandardDeviation(&v), 1.0)
+	}
+}
+
+func TestRNGPerm(t *testing.T) {
+	x := NewSeededRNG(1).Perm(10)
+	sort.Ints(x)
+	for i, v := range x {
+		if v != i {
+			t.Errorf("%v is not a permutation of 0..9", x)
+			break
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"math"
+)
+
+//
+// Regularized variants of ComputeLRCost and GradientDescent. The penalty
+// is scaled by lambda / m like the squared error term and never applies
+// to the bias term theta[0], so X carries its bias column of 1's first.
+//
+
+//
+// Elastic net LR Cost Function and gradient, mixing the L1 and L2
+// penalties by l1Ratio (0 for ridge, 1 for lasso)
+// J = (1 / (2 * m)) * sum((X * theta - y) .^ 2) + (lambda / m) * (l1Ratio * sum(abs(theta(2:end))) + ((1 - l1Ratio) / 2) * sum(theta(2:end) .^ 2))
+// grad = (1 / m) * X' * (X * theta - y) + (lambda / m) * (l1Ratio * sign(theta(2:end)) + (1 - l1Ratio) * theta(2:end))
+//
+func ComputeElasticNetCost(X Matrix, y Matrix, theta Matrix, lambda float64, l1Ratio float64) (J float64, grad Matrix) {
+	m := len(y)
+	n := len(theta)
+	grad = NewMatrix(n, 1)
+
+	for row := 0; row < m; row++ {
+		hX := 0.0
+		for col := 0; col < n; col++ {
+			hX += X[row][col] * theta[col][0]
+		}
+		r := hX - y[row][0]
+
+		J += r * r
+		for col := 0; col < n; col++ {
+			grad[col][0] += r * X[row][col]
+		}
+	}
+
+	l1, l2 := 0.0, 0.0
+	for col := 1; col < n; col++ {
+		l1 += math.Abs(theta[col][0])
+		l2 += theta[col][0] * theta[col][0]
+	}
+
+	scale := lambda / float64(m)
+	J = J/(2.0*float64(m)) + scale*(l1Ratio*l1+((1-l1Ratio)/2)*l2)
+
+	for col := 0; col < n; col++ {
+		grad[col][0] /= float64(m)
+		if col > 0 {
+			val := theta[col][0]
+			sign := 0.0
+			if val > 0 {
+				sign = 1
+			} else if val < 0 {
+				sign = -1
+			}
+			grad[col][0] += scale * (l1Ratio*sign + (1-l1Ratio)*val)
+		}
+	}
+
+	return J, grad
+}
+
+//
+// Ridge (L2) LR Cost Function and gradient
+// J = (1 / (2 * m)) * sum((X * theta - y) .^ 2) + (lambda / (2 * m)) * sum(theta(2:end) .^ 2)
+// grad = (1 / m) * X' * (X * theta - y) + (lambda / m) * [0; theta(2:end)]
+//
+func ComputeRidgeCost(X Matrix, y Matrix, theta Matrix, lambda float64) (J float64, grad Matrix) {
+	return ComputeElasticNetCost(X, y, theta, lambda, 0)
+}
+
+//
+// Lasso (L1) LR Cost Function and subgradient, taking sign(0) = 0
+// J = (1 / (2 * m)) * sum((X * theta - y) .^ 2) + (lambda / m) * sum(abs(theta(2:end)))
+// grad = (1 / m) * X' * (X * theta - y) + (lambda / m) * [0; sign(theta(2:end))]
+//
+func ComputeLassoCost(X Matrix, y Matrix, theta Matrix, lambda float64) (J float64, grad Matrix) {
+	return ComputeElasticNetCost(X, y, theta, lambda, 1)
+}
+
+//
+// GradientDescent on the ridge cost
+// theta = theta - alpha * grad
+//
+func RidgeGradientDescent(X Matrix, y Matrix, intheta Matrix, alpha float64, lambda float64, num_iters int) (theta Matrix, J_history Vector) {
+	return RidgeGradientDescentWith(X, y, intheta, NewGD(alpha), lambda, num_iters)
+}
+
+//
+// Gradient descent on the ridge cost with any optimizer, keeping the
+// last theta that lowered the cost once a step fails to
+//
+func RidgeGradientDescentWith(X Matrix, y Matrix, intheta Matrix, opt Optimizer, lambda float64, num_iters int) (theta Matrix, J_history Vector) {
+	J_history = NewEmptyVector()
+
+	theta = _copyM(intheta)
+
+	_resetOptimizer(opt)
+	Jc, grad := ComputeRidgeCost(X, y, theta, lambda)
+	for i := 0; i < num_iters; i++ {
+		next := _copyM(theta)
+		opt.Step(next, grad)
+
+		Jn, nextGrad := ComputeRidgeCost(X, y, next, lambda)
+		if Jn == Jc || Jn > Jc {
+			break
+		}
+
+		theta, grad, Jc = next, nextGrad, Jn
+		J_history = append(J_history, Jn)
+	}
+
+	return theta, J_history
+}
+
+//
+// Minimizes the lasso cost by cyclic coordinate descent, which
+// unlike gradient descent drives the weights of irrelevant
+// features to exactly 0
+//
+func LassoCoordinateDescent(X Matrix, y Matrix, intheta Matrix, lambda float64, num_iters int) (theta Matrix, J_history Vector) {
+	return ElasticNetCoordinateDescent(X, y, intheta, lambda, 1, num_iters)
+}
+
+//
+// Minimizes the elastic net cost by cyclic coordinate descent. Each
+// step solves for a single theta[j] in closed form with the others
+// fixed, soft thresholding by the L1 penalty:
+// theta(j) = S(X(:, j)' * r_j, lambda * l1Ratio) / (X(:, j)' * X(:, j) + lambda * (1 - l1Ratio))
+// where r_j is the residual excluding feature j.
+//
+func ElasticNetCoordinateDescent(X Matrix, y Matrix, intheta Matrix, lambda float64, l1Ratio float64, num_iters int) (theta Matrix, J_history Vector) {
+	J_history = NewEmptyVector()
+
+	theta = _copyM(intheta)
+
+	m := len(y)
+	n := len(theta)
+
+	// residual = y - X * theta, maintained as theta changes
+	residual := NewVector(m)
+	for row := 0; row < m; row++ {
+		residual[row] = y[row][0]
+		for col := 0; col < n; col++ {
+			residual[row] -= X[row][col] * theta[col][0]
+		}
+	}
+
+	colNorms := NewVector(n)
+	for _, row := range X {
+		for col := 0; col < n; col++ {
+			colNorms[col] += row[col] * row[col]
+		}
+	}
+
+	Jc, _ := ComputeElasticNetCost(X, y, theta, lambda, l1Ratio)
+	for i := 0; i < num_iters; i++ {
+		for col := 0; col < n; col++ {
+			if colNorms[col] == 0 {
+				continue
+			}
+
+			old := theta[col][0]
+			rho := 0.0
+			for row := 0; row < m; row++ {
+				rho += X[row][col] * (residual[row] + X[row][col]*old)
+			}
+
+			val := rho / colNorms[col]
+			if col > 0 {
+				val = _softThreshold(rho, lambda*l1Ratio) / (colNorms[col] + lambda*(1-l1Ratio))
+			}
+
+			if val != old {
+				for row := 0; row < m; row++ {
+					residual[row] -= X[row][col] * (val - old)
+				}
+				theta[col][0] = val
+			}
+		}
+
+		Jn, _ := ComputeElasticNetCost(X, y, theta, lambda, l1Ratio)
+		J_history = append(J_history, Jn)
+
+		if Jn == Jc || Jn > Jc {
+			break
+		}
+		Jc = Jn
+	}
+
+	return theta, J_history
+}
+
+//
+// Soft thresholding operator S(z, g) = sign(z) * max(abs(z) - g, 0)
+//
+func _softThreshold(z float64, g float64) (result float64) {
+	if z > g {
+		result = z - g
+	} else if z < -g {
+		result = z + g
+	}
+
+	return result
+}
@@ -0,0 +1,110 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"testing"
+)
+
+var testRegX = Matrix{{1, 1, 1}, {1, 2, -1}, {1, 3, 1}, {1, 4, -1}, {1, 5, 0.5}}
+var testRegY = Matrix{{3}, {5}, {7}, {9}, {11}}
+
+func TestComputeRidgeCost(t *testing.T) {
+	X := Matrix{{1, 1}, {1, 2}, {1, 3}}
+	y := Matrix{{2}, {3}, {4}}
+
+	J, _ := ComputeRidgeCost(X, y, Matrix{{0.5}, {0.5}}, 0)
+	_testA(t, J, Round(ComputeLRCost(X, y, Matrix{{0.5}, {0.5}}), 6))
+
+	J, grad := ComputeRidgeCost(X, y, Matrix{{1}, {1}}, 3)
+	_testA(t, J, 0.5)
+	exp := Matrix{{0}, {1}}
+	if !Equal(_roundData(grad, 6), exp) {
+		t.Errorf("%v != %v", grad, exp)
+	}
+}
+
+func TestComputeElasticNetCost(t *testing.T) {
+	theta := Matrix{{0.5}, {1.5}, {-0.5}}
+
+	J, grad := ComputeElasticNetCost(testRegX, testRegY, theta, 2, 0)
+	Jr, gradr := ComputeRidgeCost(testRegX, testRegY, theta, 2)
+	if J != Jr || !Equal(grad, gradr) {
+		t.Errorf("ratio 0 %f %v != ridge %f %v", J, grad, Jr, gradr)
+	}
+
+	J, grad = ComputeElasticNetCost(testRegX, testRegY, theta, 2, 1)
+	Jl, gradl := ComputeLassoCost(testRegX, testRegY, theta, 2)
+	if J != Jl || !Equal(grad, gradl) {
+		t.Errorf("ratio 1 %f %v != lasso %f %v", J, grad, Jl, gradl)
+	}
+
+	// lasso penalty is (lambda / m) * (1.5 + 0.5)
+	_testA(t, Jl-ComputeLRCost(testRegX, testRegY, theta), 0.8)
+}
+
+func TestRidgeGradientDescent(t *testing.T) {
+	theta, J_history := RidgeGradientDescent(testRegX, testRegY, NewMatrix(3, 1), 0.05, 0.1, 2000)
+
+	n := len(J_history)
+	if n == 0 || J_history[n-1] >= J_history[0] {
+		t.Errorf("cost did not decrease: %v", J_history)
+	}
+	if Round(theta[1][0], 1) != 2.0 {
+		t.Errorf("theta[1] %f vs expected ~%f", theta[1][0], 2.0)
+	}
+}
+
+func TestRidgeGradientDescentWith(t *testing.T) {
+	theta, J_history := RidgeGradientDescent(testRegX, testRegY, NewMatrix(3, 1), 0.05, 0.1, 2000)
+	thetaGD, J_historyGD := RidgeGradientDescentWith(testRegX, testRegY, NewMatrix(3, 1), NewGD(0.05), 0.1, 2000)
+	if !Equal(thetaGD, theta) || !Equal(J_historyGD, J_history) {
+		t.Errorf("%v != %v", thetaGD, theta)
+	}
+
+	// the optimizer state is reset, so a second fit repeats the first
+	opt := NewRMSProp(0.01, 0.9)
+	theta, J_history = RidgeGradientDescentWith(testRegX, testRegY, NewMatrix(3, 1), opt, 0.1, 2000)
+	theta2, _ := RidgeGradientDescentWith(testRegX, testRegY, NewMatrix(3, 1), opt, 0.1, 2000)
+	if !Equal(theta2, theta) {
+		t.Errorf("%v != %v", theta2, theta)
+	}
+	for i := 1; i < len(J_history); i++ {
+		if J_history[i] >= J_history[i-1] {
+			t.Fatalf("cost did not decrease at step %d: %v", i, J_history)
+		}
+	}
+}
+
+func TestLassoCoordinateDescent(t *testing.T) {
+	theta, J_history := LassoCoordinateDescent(testRegX, testRegY, NewMatrix(3, 1), 1, 100)
+
+	n := len(J_history)
+	if n < 2 || J_history[n-1] > J_history[0] {
+		t.Errorf("cost did not decrease: %v", J_history)
+	}
+
+	// the third feature is unrelated to y and is dropped
+	if theta[2][0] != 0 {
+		t.Errorf("theta[2] %f vs expected %f", theta[2][0], 0.0)
+	}
+
+	// the slope of 2 shrinks by lambda / sum((x - mean(x)) .^ 2)
+	_testA(t, theta[1][0], 1.9)
+}
+
+func TestElasticNetCoordinateDescent(t *testing.T) {
+	theta, _ := ElasticNetCoordinateDescent(testRegX, testRegY, NewMatrix(3, 1), 1, 0.5, 100)
+
+	if theta[2][0] != 0 {
+		t.Errorf("theta[2] %f vs expected %f", theta[2][0], 0.0)
+	}
+
+	// compare against the ridge solution, which keeps the third feature
+	ridge, _ := ElasticNetCoordinateDescent(testRegX, testRegY, NewMatrix(3, 1), 1, 0, 100)
+	if ridge[2][0] == 0 {
+		t.Errorf("ridge theta[2] %f vs expected non-zero", ridge[2][0])
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	. "golog"
+	"math"
+	"sort"
+)
+
+//
+// Column-wise feature scaling. A scaler is fit on the training set and
+// keeps the fitted parameters so the same transform can be applied to
+// held-out data and reverted with InverseTransform. Scale features before
+// adding the bias column of 1's, since a constant column has no spread.
+//
+type Scaler interface {
+	Fit(X Matrix)
+	Transform(X Matrix) Matrix
+	InverseTransform(X Matrix) Matrix
+}
+
+//
+// Fits the scaler to X and returns X transformed
+//
+func FitTransform(scaler Scaler, X Matrix) Matrix {
+	scaler.Fit(X)
+
+	return scaler.Transform(X)
+}
+
+//
+// Copy of column j of a matrix
+//
+func _column(matrix Matrix, j int) (column []float64) {
+	column = make([]float64, len(matrix))
+
+	for i, row := range matrix {
+		column[i] = row[j]
+	}
+
+	return column
+}
+
+//
+// Applies (x - offset[j]) / scale[j] to each column j of X, or the inverse
+// x * scale[j] + offset[j]. A zero scale is treated as 1 so that constant
+// columns are shifted but not blown up.
+//
+func _scaleColumns(X Matrix, offset Vector, scale Vector, inverse bool) (result Matrix) {
+	rows, cols := Size(X)
+	if cols != len(offset) {
+		LogErrorf("error: scaler: fitted on %d columns, got %dx%d\n", len(offset), rows, cols)
+		return nil
+	}
+
+	result = NewMatrix(rows, cols)
+	for i, row := range X {
+		for j, val := range row {
+			s := scale[j]
+			if s == 0 {
+				s = 1
+			}
+			if inverse {
+				result[i][j] = val*s + offset[j]
+			} else {
+				result[i][j] = (val - offset[j]) / s
+			}
+		}
+	}
+
+	return result
+}
+
+//
+// Standardizes each column to zero mean and unit sample standard
+// deviation (normalized by n - 1), as Octave's featureNormalize
+// x = (x - mean) / std
+//
+type StandardScaler struct {
+	Mean Vector
+	Std  Vector
+}
+
+//
+// Constructor
+//
+func NewStandardScaler() *StandardScaler {
+	return &StandardScaler{}
+}
+
+func (self *StandardScaler) Fit(X Matrix) {
+	_, cols := Size(X)
+	self.Mean = NewVector(cols)
+	self.Std = NewVector(cols)
+
+	for j := 0; j < cols; j++ {
+		column := _column(X, j)
+		self.Mean[j] = Mean(&column)
+		self.Std[j] = StandardDeviation(&column)
+
+		// StandardDeviation normalizes by n, Octave's std by n - 1
+		if n := float64(len(column)); n > 1 {
+			self.Std[j] *= math.Sqrt(n / (n - 1))
+		}
+	}
+}
+
+func (self *StandardScaler) Transform(X Matrix) Matrix {
+	return _scaleColumns(X, self.Mean, self.Std, false)
+}
+
+func (self *StandardScaler) InverseTransform(X Matrix) Matrix {
+	return _scaleColumns(X, self.Mean, self.Std, true)
+}
+
+//
+// Rescales each column linearly so the fitted minimum and maximum map
+// onto the [Low, High] feature range
+// x = Low + (x - min) * (High - Low) / (max - min)
+//
+type MinMaxScaler struct {
+	Low  float64
+	High float64
+	Min  Vector
+	Max  Vector
+}
+
+//
+// Constructor for a scaler onto the [low, high] range, eg. 0, 1
+//
+func NewMinMaxScaler(low float64, high float64) *MinMaxScaler {
+	if !(high > low) {
+		LogErrorf("error: min max scaler: empty feature range [%g, %g]\n", low, high)
+		return nil
+	}
+
+	return &MinMaxScaler{Low: low, High: high}
+}
+
+func (self *MinMaxScaler) Fit(X Matrix) {
+	_, cols := Size(X)
+	self.Min = NewVector(cols)
+	self.Max = NewVector(cols)
+
+	for j := 0; j < cols; j++ {
+		for i, row := range X {
+			if i == 0 || row[j] < self.Min[j] {
+				self.Min[j] = row[j]
+			}
+			if i == 0 || row[j] > self.Max[j] {
+				self.Max[j] = row[j]
+			}
+		}
+	}
+}
+
+//
+// Internal: the transform expressed as (x - offset) / scale
+//
+func (self *MinMaxScaler) _params() (offset Vector, scale Vector) {
+	offset = NewVector(len(self.Min))
+	scale = NewVector(len(self.Min))
+
+	width := self.High - self.Low
+	for j := range offset {
+		scale[j] = (self.Max[j] - self.Min[j]) / width
+		if scale[j] == 0 {
+			// constant column, map it to Low
+			scale[j] = 1
+		}
+		offset[j] = self.Min[j] - self.Low*scale[j]
+	}
+
+	return offset, scale
+}
+
+func (self *MinMaxScaler) Transform(X Matrix) Matrix {
+	offset, scale := self._params()
+
+	return _scaleColumns(X, offset, scale, false)
+}
+
+func (self *MinMaxScaler) InverseTransform(X Matrix) Matrix {
+	offset, scale := self._params()
+
+	return _scaleColumns(X, offset, scale, true)
+}
+
+//
+// Centers each column on its median and scales it by the interquartile
+// range, so outliers have little influence on the fitted parameters
+// x = (x - median) / (q3 - q1)
+//
+type RobustScaler struct {
+	Median Vector
+	IQR    Vector
+}
+
+//
+// Constructor
+//
+func NewRobustScaler() *RobustScaler {
+	return &RobustScaler{}
+}
+
+func (self *RobustScaler) Fit(X Matrix) {
+	_, cols := Size(X)
+	self.Median = NewVector(cols)
+	self.IQR = NewVector(cols)
+
+	for j := 0; j < cols; j++ {
+		column := _column(X, j)
+		sort.Float64s(column)
+		self.Median[j] = _quantile(&column, 0.5)
+		self.IQR[j] = _quantile(&column, 0.75) - _quantile(&column, 0.25)
+	}
+}
+
+func (self *RobustScaler) Transform(X Matrix) Matrix {
+	return _scaleColumns(X, self.Median, self.IQR, false)
+}
+
+func (self *RobustScaler) InverseTransform(X Matrix) Matrix {
+	return _scaleColumns(X, self.Median, self.IQR, true)
+}
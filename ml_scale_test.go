@@ -0,0 +1,73 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"testing"
+)
+
+var testScaleX = Matrix{{1, 10, 5}, {2, 20, 5}, {3, 30, 5}, {4, 40, 5}, {100, 50, 5}}
+
+func TestStandardScaler(t *testing.T) {
+	scaler := NewStandardScaler()
+	x := FitTransform(scaler, Matrix{{1, 2}, {3, 4}, {5, 6}})
+	// the sample standard deviation of each column is 2
+	exp := Matrix{{-1, -1}, {0, 0}, {1, 1}}
+	if !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v", x, exp)
+	}
+
+	// held-out data uses the training parameters
+	y := scaler.Transform(Matrix{{7, 2}})
+	exp = Matrix{{2, -1}}
+	if !Equal(_roundData(y, 6), exp) {
+		t.Errorf("%v != %v", y, exp)
+	}
+}
+
+func TestMinMaxScaler(t *testing.T) {
+	scaler := NewMinMaxScaler(0, 1)
+	x := FitTransform(scaler, testScaleX)
+	exp := Matrix{{0, 0, 0}, {0.010101, 0.25, 0}, {0.020202, 0.5, 0}, {0.030303, 0.75, 0}, {1, 1, 0}}
+	if !Equal(_roundData(x, 6), exp) {
+		t.Errorf("%v != %v", x, exp)
+	}
+
+	scaler = NewMinMaxScaler(-1, 1)
+	x = FitTransform(scaler, testScaleX)
+	if x[0][1] != -1 || x[4][1] != 1 || x[2][1] != 0 {
+		t.Errorf("%v not in [-1, 1]", x)
+	}
+}
+
+func TestMinMaxScalerRange(t *testing.T) {
+	if scaler := NewMinMaxScaler(1, 1); scaler != nil {
+		t.Errorf("%v vs expected nil for an empty range", scaler)
+	}
+	if scaler := NewMinMaxScaler(1, 0); scaler != nil {
+		t.Errorf("%v vs expected nil for a reversed range", scaler)
+	}
+}
+
+func TestRobustScaler(t *testing.T) {
+	scaler := NewRobustScaler()
+	x := FitTransform(scaler, testScaleX)
+
+	// the outlier does not move the median or the quartiles
+	exp := Vector{-1, -0.5, 0, 0.5, 48.5}
+	col := Vector(_column(x, 0))
+	if !Equal(col, exp) {
+		t.Errorf("%v != %v", col, exp)
+	}
+}
+
+func TestScalerInverseTransform(t *testing.T) {
+	for _, scaler := range []Scaler{NewStandardScaler(), NewMinMaxScaler(0, 1), NewRobustScaler()} {
+		x := scaler.InverseTransform(FitTransform(scaler, testScaleX))
+		if !Equal(_roundData(x, 6), testScaleX) {
+			t.Errorf("%T: %v != %v", scaler, x, testScaleX)
+		}
+	}
+}
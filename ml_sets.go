@@ -5,9 +5,10 @@
 package goml
 
 import (
+	"errors"
+	"fmt"
 	"math"
-	"math/rand"
-	"time"
+	"sort"
 )
 
 //
@@ -15,13 +16,20 @@ import (
 // range min <= value <= max
 //
 func RandomWholeFloatSet(l int, min float64, max float64) []float64 {
+	return RandomWholeFloatSetWith(DefaultRNG, l, min, max)
+}
+
+//
+// RandomWholeFloatSet drawing from rng
+//
+func RandomWholeFloatSetWith(rng *RNG, l int, min float64, max float64) []float64 {
+	rng = _rng(rng)
 	r := make([]float64, l)
 
 	s := max - min + 1
 
-	rand.Seed(time.Now().UTC().UnixNano())
 	for i := range r {
-		r[i] = float64(int(rand.Float64()*s + min))
+		r[i] = float64(int(rng.Float64()*s + min))
 	}
 
 	return r
@@ -32,13 +40,20 @@ func RandomWholeFloatSet(l int, min float64, max float64) []float64 {
 // range min <= value <= max
 //
 func RandomFloatSet(l int, min float64, max float64) []float64 {
+	return RandomFloatSetWith(DefaultRNG, l, min, max)
+}
+
+//
+// RandomFloatSet drawing from rng
+//
+func RandomFloatSetWith(rng *RNG, l int, min float64, max float64) []float64 {
+	rng = _rng(rng)
 	r := make([]float64, l)
 
 	s := max - min + 1
 
-	rand.Seed(time.Now().UTC().UnixNano())
 	for i := range r {
-		v := rand.Float64()*s + min
+		v := rng.Float64()*s + min
 		if v > max {
 			// choose floor in the event we wind up with
 			// a fractional over max
@@ -54,6 +69,14 @@ func RandomFloatSet(l int, min float64, max float64) []float64 {
 // Returns two sets of randomly selected values, a training set and testing set
 //
 func RandomTrainTestSets(inarray []float64, percent float64) (train []float64, test []float64) {
+	return RandomTrainTestSetsWith(DefaultRNG, inarray, percent)
+}
+
+//
+// RandomTrainTestSets drawing from rng
+//
+func RandomTrainTestSetsWith(rng *RNG, inarray []float64, percent float64) (train []float64, test []float64) {
+	rng = _rng(rng)
 	count := float64(len(inarray))
 
 	// round to fit the set size accurately
@@ -65,12 +88,10 @@ func RandomTrainTestSets(inarray []float64, percent float64) (train []float64, t
 	test = make([]float64, testcount)
 	testtop := 0
 
-	rand.Seed(time.Now().UTC().UnixNano())
-
 	// the logic guarantees we will have two arrays of
 	// the expected sizes
 	for _, v := range inarray {
-		if rand.Float64() <= percent && traintop < traincount {
+		if rng.Float64() <= percent && traintop < traincount {
 			train[traintop] = v
 			traintop += 1
 		} else if testtop < testcount {
@@ -89,6 +110,14 @@ func RandomTrainTestSets(inarray []float64, percent float64) (train []float64, t
 // Returns three sets of randomly selected values, a training set, validation set and testing set
 //
 func RandomTrainValidationTestSets(inarray []float64, validationpercent float64, testpercent float64) (train []float64, validation []float64, test []float64) {
+	return RandomTrainValidationTestSetsWith(DefaultRNG, inarray, validationpercent, testpercent)
+}
+
+//
+// RandomTrainValidationTestSets drawing from rng
+//
+func RandomTrainValidationTestSetsWith(rng *RNG, inarray []float64, validationpercent float64, testpercent float64) (train []float64, validation []float64, test []float64) {
+	rng = _rng(rng)
 	count := float64(len(inarray))
 
 	trainpercent := 1.0 - validationpercent - testpercent
@@ -106,12 +135,10 @@ func RandomTrainValidationTestSets(inarray []float64, validationpercent float64,
 	test = make([]float64, testcount)
 	testtop := 0
 
-	rand.Seed(time.Now().UTC().UnixNano())
-
 	// the logic guarantees we will have three arrays of
 	// the expected sizes
 	for _, v := range inarray {
-		r := rand.Float64()
+		r := rng.Float64()
 		if r <= trainpercent && traintop < traincount {
 			train[traintop] = v
 			traintop += 1
@@ -132,3 +159,160 @@ func RandomTrainValidationTestSets(inarray []float64, validationpercent float64,
 
 	return train, validation, test
 }
+
+//
+// Options for SplitRows
+//
+type SplitOptions struct {
+	Stratify bool // keep the proportion of each label of y in every split
+	RNG      *RNG // seeded generator for a reproducible shuffle, DefaultRNG when nil
+}
+
+//
+// Shuffles the rows of X and y by the same permutation and splits them
+// into sets sized by fractions of the row count, eg. 0.6, 0.2, 0.2 for
+// training, validation and test sets. When the fractions sum to less
+// than 1, a final set holds the remaining rows. y may be nil when there
+// are no labels to keep paired with X.
+//
+func SplitRows(X Matrix, y Matrix, opts *SplitOptions, fractions ...float64) (Xs []Matrix, ys []Matrix, err error) {
+	if opts == nil {
+		opts = &SplitOptions{}
+	}
+
+	rows := len(X)
+	if y != nil && len(y) != rows {
+		return nil, nil, _dimensionError("split", X, y)
+	}
+	if opts.Stratify && y == nil {
+		return nil, nil, errors.New("split: stratify requires labels")
+	}
+
+	total := 0.0
+	for _, fraction := range fractions {
+		if fraction <= 0 || fraction > 1 {
+			return nil, nil, fmt.Errorf("split: fraction %g not in (0, 1]", fraction)
+		}
+		total += fraction
+	}
+	if total > 1+_eps*float64(len(fractions)) {
+		return nil, nil, fmt.Errorf("split: fractions sum to %g, more than 1", total)
+	}
+	if len(fractions) == 0 || total < 1-_eps*float64(len(fractions)) {
+		// copy so the caller's backing array is left alone
+		fractions = append(append([]float64{}, fractions...), 1-total)
+	}
+
+	r := _rng(opts.RNG)
+
+	splits := make([][]int, len(fractions))
+	if opts.Stratify {
+		// split each label group separately so every split sees the
+		// same label proportions, then mix the groups back together
+		for _, group := range _labelGroups(Vector(_column(y, 0))) {
+			r.Shuffle(len(group), func(i int, j int) {
+				group[i], group[j] = group[j], group[i]
+			})
+			offset := 0
+			for k, count := range _splitCounts(len(group), fractions) {
+				splits[k] = append(splits[k], group[offset:offset+count]...)
+				offset += count
+			}
+		}
+		for _, split := range splits {
+			r.Shuffle(len(split), func(i int, j int) {
+				split[i], split[j] = split[j], split[i]
+			})
+		}
+	} else {
+		perm := r.Perm(rows)
+		offset := 0
+		for k, count := range _splitCounts(rows, fractions) {
+			splits[k] = perm[offset : offset+count]
+			offset += count
+		}
+	}
+
+	Xs = make([]Matrix, len(splits))
+	if y != nil {
+		ys = make([]Matrix, len(splits))
+	}
+	for k, split := range splits {
+		Xs[k] = _selectRows(X, split)
+		if y != nil {
+			ys[k] = _selectRows(y, split)
+		}
+	}
+
+	return Xs, ys, nil
+}
+
+//
+// Sizes of n items split by fractions, rounding the cumulative
+// boundaries so the sizes always sum to n
+//
+func _splitCounts(n int, fractions []float64) (counts []int) {
+	counts = make([]int, len(fractions))
+
+	cumulative := 0.0
+	bound := 0
+	for k, fraction := range fractions {
+		next := n
+		if k < len(fractions)-1 {
+			cumulative += fraction
+			next = int(math.Floor(float64(n)*cumulative + .5))
+			if next > n {
+				next = n
+			}
+		}
+		counts[k] = next - bound
+		bound = next
+	}
+
+	return counts
+}
+
+//
+// Indices grouped by label, in ascending label order. NaN labels,
+// eg. missing values, form a last group of their own.
+//
+func _labelGroups(y Vector) (groups [][]int) {
+	byLabel := make(map[float64][]int)
+	labels := []float64{}
+	var missing []int
+
+	for i, label := range y {
+		// NaN never equals itself, so it can't key the map
+		if math.IsNaN(label) {
+			missing = append(missing, i)
+			continue
+		}
+		if _, ok := byLabel[label]; !ok {
+			labels = append(labels, label)
+		}
+		byLabel[label] = append(byLabel[label], i)
+	}
+
+	sort.Float64s(labels)
+	for _, label := range labels {
+		groups = append(groups, byLabel[label])
+	}
+	if missing != nil {
+		groups = append(groups, missing)
+	}
+
+	return groups
+}
+
+//
+// Creates a matrix holding copies of the indexed rows
+//
+func _selectRows(matrix Matrix, indices []int) (result Matrix) {
+	result = NewEmptyMatrix(len(indices))
+
+	for i, index := range indices {
+		result[i] = append(Vector{}, matrix[index]...)
+	}
+
+	return result
+}
@@ -5,6 +5,7 @@
 package goml
 
 import (
+	"math"
 	"testing"
 )
 
@@ -55,3 +56,121 @@ func TestRandomTrainValidationTestSets(t *testing.T) {
 		t.Errorf("lens %d : %d : %d vs expected %d : %d : %d", len(x), len(y), len(z), 12, 4, 4)
 	}
 }
+
+func TestRandomTrainTestSetsWith(t *testing.T) {
+	in := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18}
+	x1, y1 := RandomTrainTestSetsWith(NewSeededRNG(3), in, 0.5)
+	x2, y2 := RandomTrainTestSetsWith(NewSeededRNG(3), in, 0.5)
+	if !Equal(Vector(x1), Vector(x2)) || !Equal(Vector(y1), Vector(y2)) {
+		t.Errorf("same seed gave different sets")
+	}
+}
+
+func TestSplitRows(t *testing.T) {
+	X := Seq(20, 2)
+	y := NewMatrix(20, 1)
+	for i := range y {
+		y[i][0] = X[i][0] * 10
+	}
+
+	Xs, ys, err := SplitRows(X, y, &SplitOptions{RNG: NewSeededRNG(1)}, 0.6, 0.2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(Xs) != 3 || len(Xs[0]) != 12 || len(Xs[1]) != 4 || len(Xs[2]) != 4 {
+		t.Fatalf("split sizes %d vs expected 12 : 4 : 4", len(Xs))
+	}
+
+	seen := make(map[float64]bool)
+	for k := range Xs {
+		for i, row := range Xs[k] {
+			if ys[k][i][0] != row[0]*10 {
+				t.Errorf("row %v lost its label %v", row, ys[k][i])
+			}
+			seen[row[0]] = true
+		}
+	}
+	if len(seen) != 20 {
+		t.Errorf("%d distinct rows vs expected %d", len(seen), 20)
+	}
+}
+
+func TestSplitRowsReproducible(t *testing.T) {
+	X := Seq(30, 3)
+
+	Xs1, _, _ := SplitRows(X, nil, &SplitOptions{RNG: NewSeededRNG(42)}, 0.5)
+	Xs2, _, _ := SplitRows(X, nil, &SplitOptions{RNG: NewSeededRNG(42)}, 0.5)
+	if !Equal(Xs1[0], Xs2[0]) || !Equal(Xs1[1], Xs2[1]) {
+		t.Errorf("same seed gave different splits")
+	}
+}
+
+func TestSplitRowsStratified(t *testing.T) {
+	X := Seq(40, 1)
+	y := NewMatrix(40, 1)
+	for i := 0; i < 10; i++ {
+		y[i*4][0] = 1
+	}
+
+	_, ys, err := SplitRows(X, y, &SplitOptions{Stratify: true, RNG: NewSeededRNG(7)}, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, split := range ys {
+		positives := 0
+		for _, row := range split {
+			positives += int(row[0])
+		}
+		if len(split) != 20 || positives != 5 {
+			t.Errorf("split %d has %d of %d positive vs expected 5 of 20", k, positives, len(split))
+		}
+	}
+}
+
+func TestSplitRowsStratifiedNaN(t *testing.T) {
+	X := Seq(12, 1)
+	y := NewMatrix(12, 1)
+	for i := 0; i < 4; i++ {
+		y[i*3][0] = math.NaN()
+		y[i*3+1][0] = 1
+	}
+
+	Xs, ys, err := SplitRows(X, y, &SplitOptions{Stratify: true, RNG: NewSeededRNG(3)}, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rows with a missing label are split as a class of their own
+	for k, split := range ys {
+		missing := 0
+		for _, row := range split {
+			if math.IsNaN(row[0]) {
+				missing++
+			}
+		}
+		if len(Xs[k]) != 6 || missing != 2 {
+			t.Errorf("split %d has %d of %d NaN labels vs expected 2 of 6", k, missing, len(Xs[k]))
+		}
+	}
+}
+
+func TestSplitRowsFractionsUnchanged(t *testing.T) {
+	// spare capacity the remainder fraction must not be written into
+	fractions := []float64{0.5, -1}[:1]
+
+	if _, _, err := SplitRows(Seq(4, 1), nil, nil, fractions...); err != nil {
+		t.Fatal(err)
+	}
+	if full := fractions[:2]; full[1] != -1 {
+		t.Errorf("%v vs expected the caller's [0.5 -1]", full)
+	}
+}
+
+func TestSplitRowsInvalid(t *testing.T) {
+	if _, _, err := SplitRows(Seq(4, 1), Seq(3, 1), nil, 0.5); err == nil {
+		t.Errorf("mismatched rows did not error")
+	}
+	if _, _, err := SplitRows(Seq(4, 1), nil, nil, 0.7, 0.7); err == nil {
+		t.Errorf("fractions over 1 did not error")
+	}
+}
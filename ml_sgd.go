@@ -0,0 +1,190 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	. "golog"
+	"math"
+)
+
+//
+// Cost function and gradient of theta over the rows of X and y,
+// eg. ComputeLogisticCost or ComputeRidgeCost with lambda bound
+//
+type CostFunc func(X Matrix, y Matrix, theta Matrix) (J float64, grad Matrix)
+
+//
+// Learning rate for an epoch (counting from 0) given the initial rate
+//
+type Schedule func(alpha float64, epoch int) float64
+
+//
+// Learning rate multiplied by drop every epochs epochs, a constant
+// rate when every is not positive
+// alpha(epoch) = alpha * drop ^ floor(epoch / every)
+//
+func StepDecay(drop float64, every int) Schedule {
+	if every < 1 {
+		LogErrorf("error: step decay: every %d epochs, expected at least 1\n", every)
+		return func(alpha float64, epoch int) float64 {
+			return alpha
+		}
+	}
+
+	return func(alpha float64, epoch int) float64 {
+		return alpha * math.Pow(drop, float64(epoch/every))
+	}
+}
+
+//
+// Learning rate decaying exponentially
+// alpha(epoch) = alpha * exp(-rate * epoch)
+//
+func ExponentialDecay(rate float64) Schedule {
+	return func(alpha float64, epoch int) float64 {
+		return alpha * math.Exp(-rate*float64(epoch))
+	}
+}
+
+//
+// Learning rate decaying with the inverse of time
+// alpha(epoch) = alpha / (1 + rate * epoch)
+//
+func InverseTimeDecay(rate float64) Schedule {
+	return func(alpha float64, epoch int) float64 {
+		return alpha / (1 + rate*float64(epoch))
+	}
+}
+
+//
+// Mini-batch stochastic gradient descent. Each epoch steps through
+// the training set in batches of BatchSize rows, taking a step along
+// the gradient of each batch, so a batch size of 1 is plain SGD and
+// one of m is batch gradient descent.
+//
+type SGD struct {
+	Alpha     float64   // learning rate of the default GD optimizer
+	BatchSize int       // rows per step, all rows when 0
+	Epochs    int       // maximum number of passes over the training set
+	Shuffle   bool      // visit the rows in a new random order each epoch
+	Schedule  Schedule  // optimizer rate per epoch from its initial one, constant when nil
+	Optimizer Optimizer // update rule, GD at rate Alpha when nil
+	Cost      CostFunc  // linear regression cost when nil
+	RNG       *RNG      // shuffling generator, DefaultRNG when nil
+
+	// early stopping, after Patience epochs without the cost of
+	// the validation set improving by more than Tolerance, disabled
+	// when Patience is 0. The best theta on the validation set is
+	// returned, also when the epochs run out first.
+	XVal      Matrix
+	YVal      Matrix
+	Patience  int
+	Tolerance float64
+}
+
+//
+// Constructor for a shuffling SGD
+//
+func NewSGD(alpha float64, batchSize int, epochs int) *SGD {
+	r := &SGD{Alpha: alpha, BatchSize: batchSize, Epochs: epochs, Shuffle: true}
+
+	return r
+}
+
+//
+// Linear regression cost and gradient
+//
+func _lrCost(X Matrix, y Matrix, theta Matrix) (J float64, grad Matrix) {
+	return ComputeRidgeCost(X, y, theta, 0)
+}
+
+//
+// Minimizes the cost starting from intheta, returning the cost of
+// the whole training set after each epoch in J_history
+//
+func (self *SGD) Minimize(X Matrix, y Matrix, intheta Matrix) (theta Matrix, J_history Vector) {
+	J_history = NewEmptyVector()
+
+	theta = _copyM(intheta)
+
+	cost := self.Cost
+	if cost == nil {
+		cost = _lrCost
+	}
+
+	m := len(y)
+	batchSize := self.BatchSize
+	if batchSize <= 0 || batchSize > m {
+		batchSize = m
+	}
+
+	order := make([]int, m)
+	for i := range order {
+		order[i] = i
+	}
+
+	opt := self.Optimizer
+	if opt == nil {
+		opt = NewGD(self.Alpha)
+	}
+	_resetOptimizer(opt)
+
+	// the schedule drives the rate of the optimizer from its initial
+	// one, which is put back once done
+	var rated _rated
+	alpha := 0.0
+	if self.Schedule != nil {
+		var ok bool
+		if rated, ok = opt.(_rated); ok {
+			alpha = rated.Rate()
+			defer rated.SetRate(alpha)
+		} else {
+			LogErrorf("error: sgd: %T has no learning rate to schedule\n", opt)
+		}
+	}
+
+	earlyStop := self.Patience > 0 && self.XVal != nil
+	best, bestJ, stale := theta, math.Inf(1), 0
+
+	for epoch := 0; epoch < self.Epochs; epoch++ {
+		if rated != nil {
+			rated.SetRate(self.Schedule(alpha, epoch))
+		}
+
+		if self.Shuffle {
+			_rng(self.RNG).Shuffle(m, func(i int, j int) {
+				order[i], order[j] = order[j], order[i]
+			})
+		}
+
+		for start := 0; start < m; start += batchSize {
+			end := start + batchSize
+			if end > m {
+				end = m
+			}
+
+			_, grad := cost(_selectRows(X, order[start:end]), _selectRows(y, order[start:end]), theta)
+			opt.Step(theta, grad)
+		}
+
+		J, _ := cost(X, y, theta)
+		J_history = append(J_history, J)
+
+		if earlyStop {
+			Jv, _ := cost(self.XVal, self.YVal, theta)
+			if Jv < bestJ-self.Tolerance {
+				best, bestJ, stale = _copyM(theta), Jv, 0
+			} else if stale++; stale >= self.Patience {
+				break
+			}
+		}
+	}
+
+	if earlyStop {
+		theta = best
+	}
+
+	return theta, J_history
+}
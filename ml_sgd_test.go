@@ -0,0 +1,111 @@
+// Copyright 2016, Marc Lavergne <mlavergn@gmail.com>. All rights reserved.
+// Use of this source code is governed by the MIT
+// license that can be found in the LICENSE file.
+
+package goml
+
+import (
+	"testing"
+)
+
+var testSGDX = Join(Ones(8, 1), Matrix{{-1}, {-0.75}, {-0.5}, {-0.25}, {0.25}, {0.5}, {0.75}, {1}}).(Matrix)
+var testSGDY = Matrix{{-1}, {-0.5}, {0}, {0.5}, {1.5}, {2}, {2.5}, {3}}
+
+func TestSchedules(t *testing.T) {
+	_testAB(t, StepDecay(0.5, 10)(0.1, 9), 0.1, StepDecay(0.5, 10)(0.1, 25), 0.025)
+	_testA(t, ExponentialDecay(0.1)(1, 10), 0.367879)
+	_testA(t, InverseTimeDecay(0.5)(1, 2), 0.5)
+
+	// no step length, the rate stays constant
+	_testAB(t, StepDecay(0.5, 0)(0.1, 25), 0.1, StepDecay(0.5, -3)(0.1, 25), 0.1)
+}
+
+func TestSGD(t *testing.T) {
+	sgd := NewSGD(0.1, 2, 200)
+	sgd.RNG = NewSeededRNG(1)
+	theta, J_history := sgd.Minimize(testSGDX, testSGDY, NewMatrix(2, 1))
+
+	// y = 2 * x + 1
+	exp := Matrix{{1}, {2}}
+	if !Equal(_roundData(theta, 3), exp) {
+		t.Errorf("%v != %v", theta, exp)
+	}
+	if len(J_history) != 200 || J_history[199] >= J_history[0] {
+		t.Errorf("%d epochs, cost %f -> %f", len(J_history), J_history[0], J_history[len(J_history)-1])
+	}
+}
+
+func TestSGDFullBatch(t *testing.T) {
+	// without batches or shuffling every epoch is a gradient descent step
+	sgd := &SGD{Alpha: 0.1, Epochs: 20}
+	theta, J_history := sgd.Minimize(testSGDX, testSGDY, NewMatrix(2, 1))
+	gdTheta, gdJ_history := GradientDescent(testSGDX, testSGDY, NewMatrix(2, 1), 0.1, 20)
+
+	if !Equal(_roundData(theta, 9), _roundData(gdTheta, 9)) {
+		t.Errorf("%v != %v", theta, gdTheta)
+	}
+	if !Equal(_roundData(J_history, 9), _roundData(gdJ_history, 9)) {
+		t.Errorf("%v != %v", J_history, gdJ_history)
+	}
+}
+
+func TestSGDEarlyStopping(t *testing.T) {
+	sgd := NewSGD(0.1, 4, 500)
+	sgd.RNG = NewSeededRNG(1)
+	sgd.Schedule = InverseTimeDecay(0.01)
+
+	// the validation set follows y = x + 1, which training drifts away from
+	sgd.XVal = Matrix{{1, 0}, {1, 1}}
+	sgd.YVal = Matrix{{1}, {2}}
+	sgd.Patience = 5
+
+	theta, J_history := sgd.Minimize(testSGDX, testSGDY, NewMatrix(2, 1))
+	if len(J_history) >= 500 {
+		t.Errorf("ran all %d epochs vs expected an early stop", len(J_history))
+	}
+	// the best validation cost comes while the slope passes 1 on its way to 2
+	if theta[1][0] >= 1.5 {
+		t.Errorf("theta %v vs expected a slope near 1", theta)
+	}
+}
+
+func TestSGDEarlyStoppingEpochs(t *testing.T) {
+	XVal, YVal := Matrix{{1, 0}, {1, 1}}, Matrix{{1}, {2}}
+
+	last := NewSGD(0.1, 4, 100)
+	last.RNG = NewSeededRNG(1)
+	lastTheta, _ := last.Minimize(testSGDX, testSGDY, NewMatrix(2, 1))
+
+	// the epochs run out before the patience does
+	sgd := NewSGD(0.1, 4, 100)
+	sgd.RNG = NewSeededRNG(1)
+	sgd.XVal, sgd.YVal = XVal, YVal
+	sgd.Patience = 1000
+
+	theta, J_history := sgd.Minimize(testSGDX, testSGDY, NewMatrix(2, 1))
+	if len(J_history) != 100 {
+		t.Errorf("%d epochs vs expected %d", len(J_history), 100)
+	}
+	Jv, _ := _lrCost(XVal, YVal, theta)
+	lastJv, _ := _lrCost(XVal, YVal, lastTheta)
+	if Jv >= lastJv {
+		t.Errorf("validation cost %f vs expected the best, below the last %f", Jv, lastJv)
+	}
+}
+
+func TestSGDLogistic(t *testing.T) {
+	X := Join(Ones(6, 1), Matrix{{-3}, {-2}, {-1}, {1}, {2}, {3}}).(Matrix)
+	y := Matrix{{0}, {0}, {0}, {1}, {1}, {1}}
+
+	sgd := NewSGD(0.5, 1, 50)
+	sgd.RNG = NewSeededRNG(1)
+	sgd.Cost = func(X Matrix, y Matrix, theta Matrix) (float64, Matrix) {
+		return ComputeLogisticCost(X, y, theta, 0)
+	}
+
+	theta, _ := sgd.Minimize(X, y, NewMatrix(2, 1))
+	model := &LogisticRegression{Theta: theta, Threshold: 0.5}
+	if !Equal(model.Predict(X), y) {
+		t.Errorf("%v != %v", model.Predict(X), y)
+	}
+}
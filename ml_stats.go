@@ -32,11 +32,33 @@ func Round(v float64, p int) (result float64) {
 }
 
 //
-// Median of a vector
+// Median of a sorted vector
 //
 func Median(v *[]float64) (result float64) {
 	n := len(*v) / 2
-	result = ((*v)[n] + (*v)[n-1]) / 2
+	if len(*v)%2 == 1 {
+		result = (*v)[n]
+	} else {
+		result = ((*v)[n] + (*v)[n-1]) / 2
+	}
+
+	return result
+}
+
+//
+// Quantile q (0 <= q <= 1) of an ascending sorted vector, linearly
+// interpolating between the closest ranks
+//
+func _quantile(v *[]float64, q float64) (result float64) {
+	n := len(*v)
+	if n == 0 {
+		return math.NaN()
+	}
+
+	pos := q * float64(n-1)
+	low := int(math.Floor(pos))
+	high := int(math.Ceil(pos))
+	result = (*v)[low] + (pos-float64(low))*((*v)[high]-(*v)[low])
 
 	return result
 }
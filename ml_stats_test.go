@@ -25,6 +25,9 @@ func _testA(t *testing.T, aIn float64, aExp float64) {
 
 func TestMedian(t *testing.T) {
 	_testAB(t, Median(&testA), 7.0, Median(&testB), 15.0)
+
+	odd := []float64{1, 2, 4}
+	_testA(t, Median(&odd), 2.0)
 }
 
 func TestSum(t *testing.T) {
@@ -5,7 +5,8 @@
 package goml
 
 import (
-	. "golog"
+	"errors"
+	"fmt"
 	"reflect"
 )
 
@@ -27,9 +28,10 @@ const (
 )
 
 //
-// Generate a bitmask based on the argument data types
+// Generate a bitmask based on the argument data types. An unhandled
+// type is reported as a TypeError for the named operation.
 //
-func _argBitmask(arg1 Data, arg2 Data) (flags ArgBitmask) {
+func _argBitmask(op string, arg1 Data, arg2 Data) (flags ArgBitmask, err error) {
 	switch arg1.(type) {
 	case Matrix, [][]float64:
 		flags |= ARG1_MATRIX
@@ -38,7 +40,7 @@ func _argBitmask(arg1 Data, arg2 Data) (flags ArgBitmask) {
 	case float64, float32, int:
 		flags |= ARG1_SCALAR
 	default:
-		LogErrorf("Unhandled argument type: %s", reflect.TypeOf(arg1))
+		err = &TypeError{Op: op, Type1: reflect.TypeOf(arg1), Type2: reflect.TypeOf(arg2)}
 	}
 
 	switch arg2.(type) {
@@ -49,8 +51,128 @@ func _argBitmask(arg1 Data, arg2 Data) (flags ArgBitmask) {
 	case float64, float32, int:
 		flags |= ARG2_SCALAR
 	default:
-		LogErrorf("Unhandled argument type: %s", reflect.TypeOf(arg2))
+		err = &TypeError{Op: op, Type1: reflect.TypeOf(arg1), Type2: reflect.TypeOf(arg2)}
 	}
 
-	return flags
+	return flags, err
+}
+
+//
+// Converts any of the handled scalar types to a float64
+//
+func _scalar(arg Data) (scalar float64) {
+	switch arg.(type) {
+	case float64:
+		scalar = arg.(float64)
+	case float32:
+		scalar = float64(arg.(float32))
+	case int:
+		scalar = float64(arg.(int))
+	}
+
+	return scalar
+}
+
+//
+// Error reported when the operand shapes are nonconformant
+// for an operation, eg. adding a 2x3 and a 3x2 matrix.
+//
+type DimensionError struct {
+	Op    string
+	Rows1 int
+	Cols1 int
+	Rows2 int
+	Cols2 int
+}
+
+func (self *DimensionError) Error() string {
+	return fmt.Sprintf("operator %s: nonconformant arguments (op1 is %dx%d, op2 is %dx%d)", self.Op, self.Rows1, self.Cols1, self.Rows2, self.Cols2)
+}
+
+//
+// Creates a DimensionError recording the shapes of both operands
+//
+func _dimensionError(op string, arg1 Data, arg2 Data) (err *DimensionError) {
+	rows, cols := Size(arg1)
+	rows2, cols2 := Size(arg2)
+	err = &DimensionError{Op: op, Rows1: rows, Cols1: cols, Rows2: rows2, Cols2: cols2}
+
+	return err
+}
+
+//
+// Reported by operations that require a square matrix
+//
+var ErrNotSquare = errors.New("argument must be a square matrix")
+
+//
+// Reported when a matrix cannot be inverted or factored because it
+// is singular, or nearly so, to machine precision
+//
+var ErrSingular = errors.New("matrix singular to machine precision")
+
+//
+// Reported by factorizations that require a symmetric positive
+// definite matrix, eg. a covariance or kernel matrix
+//
+var ErrNotPositiveDefinite = errors.New("input matrix must be positive definite")
+
+//
+// Error reported by the loaders for a cell that can't be read,
+// located by its 1-based line and column in the input
+//
+type ParseError struct {
+	Row   int
+	Col   int
+	Value string
+	Err   error
+}
+
+func (self *ParseError) Error() string {
+	return fmt.Sprintf("parse error on line %d, column %d (%q): %v", self.Row, self.Col, self.Value, self.Err)
+}
+
+//
+// Unwraps the underlying error, eg. a strconv.NumError
+//
+func (self *ParseError) Unwrap() error {
+	return self.Err
+}
+
+//
+// Error reported when an operation is given an unhandled
+// argument type or combination of types.
+//
+type TypeError struct {
+	Op    string
+	Type1 reflect.Type
+	Type2 reflect.Type
+}
+
+func (self *TypeError) Error() string {
+	return fmt.Sprintf("operator %s: unhandled argument types (op1 is %v, op2 is %v)", self.Op, self.Type1, self.Type2)
+}
+
+//
+// Adapts a matrix result to the generic Data result so that a
+// failed operation returns a nil Data rather than a typed nil.
+//
+func _dataM(matrix Matrix, err error) (Data, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return matrix, nil
+}
+
+//
+// Adapts a vector result to the generic Data result so that a
+// failed operation returns a nil Data rather than a typed nil.
+//
+func _dataV(vector Vector, err error) (Data, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return vector, nil
 }